@@ -0,0 +1,307 @@
+package client
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	uriTag = "uri"
+
+	// uriDateLayout is the layout of time.Time fields tagged with the date
+	// option.
+	uriDateLayout = time.DateOnly
+)
+
+var textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+
+// uriVariable is a variable of a URI template read from a struct field.
+type uriVariable struct {
+	name    string
+	value   interface{}
+	explode bool
+}
+
+// AssignStruct assigns the exported fields of the struct v, or pointer to
+// struct, as variables of the URI template. Variables are named by the `uri`
+// tag of the field, or the field name if there is none, and fields tagged
+// with `uri:"-"` are skipped. Fields of embedded structs are assigned as if
+// they were fields of v.
+//
+// Nil pointers are left unassigned, so optional query parameters are omitted
+// when expanded. Values implementing encoding.TextMarshaler are assigned as
+// their text, time.Time as RFC 3339 or with the `date` or `unix` option as a
+// date or Unix seconds, and other structs as a map of their fields.
+//
+// The `explode` option expands the variable with the explode modifier, as if
+// it was written `{?name*}` in the template, such that every element of a
+// slice, or entry of a map, is a separate query parameter.
+//
+//	type NodeFilter struct {
+//		Pagination
+//		Type    *string   `uri:"type"`
+//		Tags    []string  `uri:"tag,explode"`
+//		Created time.Time `uri:"createdAfter,date"`
+//	}
+//
+//	client.Get("nodes{?type,tag,createdAfter,limit}").AssignStruct(filter)
+func (r *Request) AssignStruct(v interface{}) *Request {
+	rv := reflect.ValueOf(v)
+
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return r
+		}
+
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		r.assignErr = fmt.Errorf("expected a struct, got %T", v)
+		return r
+	}
+
+	variables, err := structVariables(rv)
+	if err != nil {
+		r.assignErr = fmt.Errorf("%s: %w", rv.Type(), err)
+		return r
+	}
+
+	for _, variable := range variables {
+		r.uriVariables[variable.name] = variable.value
+
+		if variable.explode {
+			if r.explodedVariables == nil {
+				r.explodedVariables = make(map[string]bool)
+			}
+
+			r.explodedVariables[variable.name] = true
+		} else {
+			delete(r.explodedVariables, variable.name)
+		}
+	}
+
+	return r
+}
+
+func structVariables(rv reflect.Value) ([]uriVariable, error) {
+	var variables []uriVariable
+
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		value := rv.Field(i)
+
+		tag := field.Tag.Get(uriTag)
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			if embedded, ok := embeddedStruct(value); ok {
+				nested, err := structVariables(embedded)
+				if err != nil {
+					return nil, err
+				}
+
+				variables = append(variables, nested...)
+
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		converted, ok, err := uriValue(value, options)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		if ok {
+			variables = append(variables, uriVariable{
+				name:    name,
+				value:   converted,
+				explode: hasTagOption(options, "explode"),
+			})
+		}
+	}
+
+	return variables, nil
+}
+
+// embeddedStruct returns the struct of an embedded field whose fields are
+// promoted, which excludes nil pointers and structs with a text form.
+func embeddedStruct(value reflect.Value) (reflect.Value, bool) {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return value, false
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct || value.Type() == reflect.TypeFor[time.Time]() {
+		return value, false
+	}
+
+	return value, !reflect.PointerTo(value.Type()).Implements(textMarshalerType)
+}
+
+// uriValue converts the value into a value the URI template can expand,
+// reporting false if it is a nil pointer or interface.
+func uriValue(value reflect.Value, options string) (interface{}, bool, error) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil, false, nil
+		}
+
+		value = value.Elem()
+	}
+
+	if t, ok := value.Interface().(time.Time); ok {
+		switch {
+		case hasTagOption(options, "unix"):
+			return strconv.FormatInt(t.Unix(), 10), true, nil
+		case hasTagOption(options, "date"):
+			return t.Format(uriDateLayout), true, nil
+		default:
+			return t.Format(time.RFC3339Nano), true, nil
+		}
+	}
+
+	if reflect.PointerTo(value.Type()).Implements(textMarshalerType) {
+		addressable := reflect.New(value.Type())
+		addressable.Elem().Set(value)
+
+		text, err := addressable.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, false, err
+		}
+
+		return string(text), true, nil
+	}
+
+	switch value.Kind() {
+	case reflect.Array, reflect.Slice:
+		elements := make([]interface{}, 0, value.Len())
+
+		for i := 0; i < value.Len(); i++ {
+			element, ok, err := uriValue(value.Index(i), options)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if ok {
+				elements = append(elements, element)
+			}
+		}
+
+		return elements, true, nil
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return nil, false, fmt.Errorf("unsupported map key type %s", value.Type().Key())
+		}
+
+		entries := make(map[string]interface{}, value.Len())
+
+		iter := value.MapRange()
+		for iter.Next() {
+			entry, ok, err := uriValue(iter.Value(), options)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if ok {
+				entries[iter.Key().String()] = entry
+			}
+		}
+
+		return entries, true, nil
+	case reflect.Struct:
+		variables, err := structVariables(value)
+		if err != nil {
+			return nil, false, err
+		}
+
+		entries := make(map[string]interface{}, len(variables))
+		for _, variable := range variables {
+			entries[variable.name] = variable.value
+		}
+
+		return entries, true, nil
+	default:
+		return value.Interface(), true, nil
+	}
+}
+
+func hasTagOption(options, option string) bool {
+	for options != "" {
+		var current string
+
+		current, options, _ = strings.Cut(options, ",")
+		if current == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// explodeTemplate adds the explode modifier to the references of the
+// variables in the URI template, unless they already have a modifier.
+func explodeTemplate(template string, variables map[string]bool) string {
+	if len(variables) == 0 {
+		return template
+	}
+
+	var exploded strings.Builder
+
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+
+		end += start
+
+		expression := template[start+1 : end]
+		operator := ""
+
+		if expression != "" && strings.ContainsRune("+#./;?&", rune(expression[0])) {
+			operator, expression = expression[:1], expression[1:]
+		}
+
+		terms := strings.Split(expression, ",")
+		for i, term := range terms {
+			if variables[term] {
+				terms[i] = term + "*"
+			}
+		}
+
+		exploded.WriteString(template[:start+1])
+		exploded.WriteString(operator)
+		exploded.WriteString(strings.Join(terms, ","))
+
+		template = template[end:]
+	}
+
+	exploded.WriteString(template)
+
+	return exploded.String()
+}
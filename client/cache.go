@@ -0,0 +1,416 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-http-utils/headers"
+
+	"github.com/SKF/go-rest-utility/client/cache"
+)
+
+const (
+	ageHeader  = "Age"
+	dateHeader = "Date"
+)
+
+// MaxCachedBodySize is the largest response body stored by CacheMiddleware,
+// larger responses are passed through without being cached.
+const MaxCachedBodySize = 4 << 20
+
+// heuristicallyCacheableStatusCodes are the status codes which may be cached
+// without an explicit expiration time, see RFC 9110 section 15.1.
+var heuristicallyCacheableStatusCodes = []int{
+	http.StatusOK,
+	http.StatusNonAuthoritativeInfo,
+	http.StatusNoContent,
+	http.StatusMultipleChoices,
+	http.StatusMovedPermanently,
+	http.StatusPermanentRedirect,
+	http.StatusNotFound,
+	http.StatusMethodNotAllowed,
+	http.StatusGone,
+	http.StatusRequestURITooLong,
+	http.StatusNotImplemented,
+}
+
+// CacheMiddleware caches GET responses in the store as a private cache
+// following RFC 9111.
+//
+// Fresh responses are served from the store without a request, as determined
+// by `Cache-Control`, `Expires` and `Vary`. Stale responses are revalidated
+// with `If-None-Match` and `If-Modified-Since`, and served from the store if
+// the server responds 304 Not Modified. Range requests, streams and bodies
+// larger than MaxCachedBodySize are passed through without being cached. Successful unsafe requests, and those
+// failing with 412 Precondition Failed, invalidate the cached response of
+// their URL.
+//
+// Responses are cached per `Authorization` header of the requests, but the
+// TokenProvider of the Client authorizes requests after the cache, so the
+// store must never be shared between clients with different credentials,
+// unless scoped with cache.NewScopedStore.
+func CacheMiddleware(store cache.Store) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			key := cacheKey(req)
+
+			if req.Method != http.MethodGet {
+				resp, err := next.RoundTrip(req)
+				if err == nil && !isSafeMethod(req.Method) && invalidatesCache(resp.StatusCode) {
+					store.Delete(key)
+				}
+
+				return resp, err
+			}
+
+			requestDirectives := parseCacheControl(req.Header)
+			if requestDirectives.has("no-store") || isConditionalRequest(req) || req.Header.Get(headers.Range) != "" {
+				return next.RoundTrip(req)
+			}
+
+			entry, cached := loadCacheEntry(store, key, req)
+			if cached && entry.isFresh(time.Now(), requestDirectives) {
+				return entry.response(req, time.Now()), nil
+			}
+
+			outgoing := req
+			if cached && entry.hasValidators() {
+				outgoing = req.Clone(req.Context())
+
+				if etag := entry.Header.Get(headers.ETag); etag != "" {
+					outgoing.Header.Set(headers.IfNoneMatch, etag)
+				}
+
+				if lastModified := entry.Header.Get(headers.LastModified); lastModified != "" {
+					outgoing.Header.Set(headers.IfModifiedSince, lastModified)
+				}
+			}
+
+			requestTime := time.Now()
+
+			resp, err := next.RoundTrip(outgoing)
+			if err != nil {
+				return nil, err
+			}
+
+			responseTime := time.Now()
+
+			if outgoing != req && resp.StatusCode == http.StatusNotModified {
+				discardResponse(resp)
+
+				entry.revalidated(resp.Header, requestTime, responseTime)
+				entry.save(store, key)
+
+				return entry.response(req, responseTime), nil
+			}
+
+			if !isCacheableResponse(resp) || resp.ContentLength > MaxCachedBodySize {
+				return resp, nil
+			}
+
+			body, err := io.ReadAll(io.LimitReader(resp.Body, MaxCachedBodySize+1))
+			if err != nil {
+				resp.Body.Close() //nolint: errcheck
+				return nil, err
+			}
+
+			if len(body) > MaxCachedBodySize {
+				// The stored response, if any, is outdated but can not be
+				// replaced.
+				store.Delete(key)
+
+				resp.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+
+				return resp, nil
+			}
+
+			resp.Body.Close() //nolint: errcheck
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+
+			entry = &cacheEntry{
+				StatusCode:   resp.StatusCode,
+				Status:       resp.Status,
+				Header:       resp.Header.Clone(),
+				Body:         body,
+				Vary:         varyHeader(req, resp),
+				RequestTime:  requestTime,
+				ResponseTime: responseTime,
+			}
+			entry.save(store, key)
+
+			return resp, nil
+		})
+	}
+}
+
+// cacheKey is the key of the response to the request, which is kept apart
+// for every credential the request is sent with. The credentials of the
+// TokenProvider are added after the cache and are not part of the key.
+func cacheKey(req *http.Request) string {
+	key := http.MethodGet + " " + req.URL.String()
+
+	if authorization := req.Header.Get(headers.Authorization); authorization != "" {
+		hash := sha256.Sum256([]byte(authorization))
+		key += "\n" + headers.Authorization + ": " + hex.EncodeToString(hash[:])
+	}
+
+	return key
+}
+
+// invalidatesCache reports whether a response to an unsafe request means the
+// cached response is, or may be, outdated. A failed precondition means the
+// resource has changed since the entity tag the request was made with.
+func invalidatesCache(statusCode int) bool {
+	return statusCode < http.StatusBadRequest || statusCode == http.StatusPreconditionFailed
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+// isConditionalRequest reports whether the caller has made the request
+// conditional itself, in which case the response is left to the caller.
+func isConditionalRequest(req *http.Request) bool {
+	for _, header := range []string{headers.IfNoneMatch, headers.IfModifiedSince, headers.IfMatch, headers.IfUnmodifiedSince, headers.IfRange} {
+		if req.Header.Get(header) != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isCacheableResponse(resp *http.Response) bool {
+	if !slices.Contains(heuristicallyCacheableStatusCodes, resp.StatusCode) {
+		return false
+	}
+
+	directives := parseCacheControl(resp.Header)
+	if directives.has("no-store") {
+		return false
+	}
+
+	// Streams are never complete, so they are not buffered to be stored.
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get(headers.ContentType)) //nolint: errcheck
+	if mediaType == eventStreamMediaType || mediaType == ndjsonMediaType {
+		return false
+	}
+
+	if slices.Contains(varyHeaderNames(resp.Header), "*") {
+		return false
+	}
+
+	// Without an expiration time or validators the response could never be
+	// served from the cache.
+	return directives.has("max-age") ||
+		resp.Header.Get(headers.Expires) != "" ||
+		resp.Header.Get(headers.ETag) != "" ||
+		resp.Header.Get(headers.LastModified) != ""
+}
+
+func varyHeaderNames(header http.Header) []string {
+	var names []string
+
+	for _, value := range header.Values(headers.Vary) {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	return names
+}
+
+// varyHeader returns the request headers which select the response.
+func varyHeader(req *http.Request, resp *http.Response) http.Header {
+	vary := http.Header{}
+
+	for _, name := range varyHeaderNames(resp.Header) {
+		vary[name] = slices.Clone(req.Header.Values(name))
+	}
+
+	return vary
+}
+
+type cacheEntry struct {
+	StatusCode   int         `json:"statusCode"`
+	Status       string      `json:"status"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	Vary         http.Header `json:"vary"`
+	RequestTime  time.Time   `json:"requestTime"`
+	ResponseTime time.Time   `json:"responseTime"`
+}
+
+// loadCacheEntry returns the entry stored for the request, unless it was
+// stored for a request with different values of the headers in Vary.
+func loadCacheEntry(store cache.Store, key string, req *http.Request) (*cacheEntry, bool) {
+	value, found := store.Get(key)
+	if !found {
+		return nil, false
+	}
+
+	entry := new(cacheEntry)
+	if err := json.Unmarshal(value, entry); err != nil {
+		return nil, false
+	}
+
+	for name, values := range entry.Vary {
+		if strings.Join(values, ",") != strings.Join(req.Header.Values(name), ",") {
+			return nil, false
+		}
+	}
+
+	return entry, true
+}
+
+func (e *cacheEntry) save(store cache.Store, key string) {
+	if value, err := json.Marshal(e); err == nil {
+		store.Set(key, value)
+	}
+}
+
+func (e *cacheEntry) hasValidators() bool {
+	return e.Header.Get(headers.ETag) != "" || e.Header.Get(headers.LastModified) != ""
+}
+
+// revalidated updates the stored header with the header of a 304 response,
+// see RFC 9111 section 4.3.4.
+func (e *cacheEntry) revalidated(header http.Header, requestTime, responseTime time.Time) {
+	for name, values := range header {
+		if name != headers.ContentLength {
+			e.Header[name] = values
+		}
+	}
+
+	e.RequestTime = requestTime
+	e.ResponseTime = responseTime
+}
+
+func (e *cacheEntry) isFresh(now time.Time, requestDirectives cacheControl) bool {
+	if requestDirectives.has("no-cache") || parseCacheControl(e.Header).has("no-cache") {
+		return false
+	}
+
+	lifetime := e.freshnessLifetime()
+	if maxAge, ok := requestDirectives.seconds("max-age"); ok {
+		lifetime = min(lifetime, maxAge)
+	}
+
+	return e.age(now) < lifetime
+}
+
+// freshnessLifetime is calculated as in RFC 9111 section 4.2.1, with the
+// heuristic of section 4.2.2.
+func (e *cacheEntry) freshnessLifetime() time.Duration {
+	if maxAge, ok := parseCacheControl(e.Header).seconds("max-age"); ok {
+		return maxAge
+	}
+
+	if expires := e.Header.Get(headers.Expires); expires != "" {
+		expiresAt, err := http.ParseTime(expires)
+		if err != nil {
+			return 0
+		}
+
+		return expiresAt.Sub(e.date())
+	}
+
+	if lastModified, err := http.ParseTime(e.Header.Get(headers.LastModified)); err == nil {
+		return e.date().Sub(lastModified) / 10
+	}
+
+	return 0
+}
+
+func (e *cacheEntry) date() time.Time {
+	if date, err := http.ParseTime(e.Header.Get(dateHeader)); err == nil {
+		return date
+	}
+
+	return e.ResponseTime
+}
+
+// age is calculated as in RFC 9111 section 4.2.3.
+func (e *cacheEntry) age(now time.Time) time.Duration {
+	apparentAge := max(0, e.ResponseTime.Sub(e.date()))
+
+	ageValue, err := strconv.ParseInt(e.Header.Get(ageHeader), 10, 64)
+	if err != nil {
+		ageValue = 0
+	}
+
+	responseDelay := e.ResponseTime.Sub(e.RequestTime)
+	correctedInitialAge := max(apparentAge, time.Duration(ageValue)*time.Second+responseDelay)
+
+	return correctedInitialAge + now.Sub(e.ResponseTime)
+}
+
+func (e *cacheEntry) response(req *http.Request, now time.Time) *http.Response {
+	header := e.Header.Clone()
+	header.Set(ageHeader, strconv.FormatInt(int64(e.age(now)/time.Second), 10))
+
+	return &http.Response{
+		Status:        e.Status,
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// cacheControl holds the directives of a Cache-Control header.
+type cacheControl map[string]string
+
+func parseCacheControl(header http.Header) cacheControl {
+	directives := cacheControl{}
+
+	for _, value := range header.Values(headers.CacheControl) {
+		for _, directive := range strings.Split(value, ",") {
+			name, argument, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if name == "" {
+				continue
+			}
+
+			directives[strings.ToLower(name)] = strings.Trim(argument, `"`)
+		}
+	}
+
+	return directives
+}
+
+func (c cacheControl) has(directive string) bool {
+	_, exists := c[directive]
+	return exists
+}
+
+func (c cacheControl) seconds(directive string) (time.Duration, bool) {
+	seconds, err := strconv.ParseInt(c[directive], 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
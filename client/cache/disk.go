@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DiskStore is a Store keeping every entry in a file of a directory, such
+// that the cache survives restarts.
+type DiskStore struct {
+	dir string
+}
+
+// NewDiskStore creates a DiskStore in the directory, which is created if it
+// does not exist.
+func NewDiskStore(dir string) (*DiskStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("unable to create cache directory: %w", err)
+	}
+
+	return &DiskStore{dir: dir}, nil
+}
+
+func (s *DiskStore) Get(key string) ([]byte, bool) {
+	value, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	return value, true
+}
+
+// Set writes the entry to a temporary file which is then renamed, such that
+// a concurrent Get never reads a partially written entry.
+func (s *DiskStore) Set(key string, value []byte) {
+	file, err := os.CreateTemp(s.dir, "tmp-*")
+	if err != nil {
+		return
+	}
+
+	_, err = file.Write(value)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(file.Name(), s.path(key))
+	}
+
+	if err != nil {
+		os.Remove(file.Name()) //nolint: errcheck
+	}
+}
+
+func (s *DiskStore) Delete(key string) {
+	os.Remove(s.path(key)) //nolint: errcheck
+}
+
+func (s *DiskStore) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(hash[:]))
+}
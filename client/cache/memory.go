@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+const DefaultMaxEntries = 1000
+
+// MemoryStore is an in-memory Store which evicts the least recently used
+// entry when it is full.
+type MemoryStore struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key   string
+	value []byte
+}
+
+// NewMemoryStore creates a MemoryStore holding at most maxEntries entries,
+// DefaultMaxEntries if not positive.
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+func (s *MemoryStore) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, exists := s.entries[key]
+	if !exists {
+		return nil, false
+	}
+
+	s.order.MoveToFront(element)
+
+	return element.Value.(*memoryEntry).value, true
+}
+
+func (s *MemoryStore) Set(key string, value []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, exists := s.entries[key]; exists {
+		element.Value.(*memoryEntry).value = value
+		s.order.MoveToFront(element)
+
+		return
+	}
+
+	s.entries[key] = s.order.PushFront(&memoryEntry{key: key, value: value})
+
+	for s.order.Len() > s.maxEntries {
+		oldest := s.order.Back()
+
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryEntry).key)
+	}
+}
+
+func (s *MemoryStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, exists := s.entries[key]; exists {
+		s.order.Remove(element)
+		delete(s.entries, key)
+	}
+}
+
+// Len returns the number of entries in the store.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.order.Len()
+}
@@ -0,0 +1,33 @@
+package cache
+
+// ScopedStore is a Store sharing the entries of another Store, kept apart
+// from the entries of other scopes.
+type ScopedStore struct {
+	store Store
+	scope string
+}
+
+// NewScopedStore creates a ScopedStore storing its entries in store, such
+// that clients with different credentials can share a store, or a DiskStore
+// directory, by scoping it to their principal.
+//
+//	c := client.NewClient(client.WithCache(cache.NewScopedStore(shared, tenantID)))
+func NewScopedStore(store Store, scope string) *ScopedStore {
+	return &ScopedStore{store: store, scope: scope}
+}
+
+func (s *ScopedStore) Get(key string) ([]byte, bool) {
+	return s.store.Get(s.key(key))
+}
+
+func (s *ScopedStore) Set(key string, value []byte) {
+	s.store.Set(s.key(key), value)
+}
+
+func (s *ScopedStore) Delete(key string) {
+	s.store.Delete(s.key(key))
+}
+
+func (s *ScopedStore) key(key string) string {
+	return s.scope + "\n" + key
+}
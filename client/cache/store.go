@@ -0,0 +1,13 @@
+// Package cache provides storage for the HTTP response cache of the client.
+package cache
+
+// Store stores serialised cache entries by key.
+//
+// A Store is used concurrently and must be safe for concurrent use. Entries
+// which can not be read are reported as missing, and entries which can not
+// be written are dropped, as the cache only serves to avoid requests.
+type Store interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte)
+	Delete(key string)
+}
@@ -0,0 +1,78 @@
+package cache_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client/cache"
+)
+
+func TestMemoryStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	store := cache.NewMemoryStore(2)
+
+	store.Set("a", []byte("1"))
+	store.Set("b", []byte("2"))
+
+	_, found := store.Get("a")
+	require.True(t, found)
+
+	store.Set("c", []byte("3"))
+
+	_, found = store.Get("b")
+	require.False(t, found)
+
+	value, found := store.Get("a")
+	require.True(t, found)
+	require.Equal(t, []byte("1"), value)
+	require.Equal(t, 2, store.Len())
+
+	store.Delete("a")
+
+	_, found = store.Get("a")
+	require.False(t, found)
+}
+
+func TestScopedStore(t *testing.T) {
+	shared := cache.NewMemoryStore(10)
+	operator := cache.NewScopedStore(shared, "operator")
+	admin := cache.NewScopedStore(shared, "admin")
+
+	operator.Set("GET https://example.com/nodes", []byte("operator"))
+	admin.Set("GET https://example.com/nodes", []byte("admin"))
+
+	value, found := operator.Get("GET https://example.com/nodes")
+	require.True(t, found)
+	require.Equal(t, []byte("operator"), value)
+
+	_, found = shared.Get("GET https://example.com/nodes")
+	require.False(t, found)
+
+	admin.Delete("GET https://example.com/nodes")
+
+	_, found = admin.Get("GET https://example.com/nodes")
+	require.False(t, found)
+
+	_, found = operator.Get("GET https://example.com/nodes")
+	require.True(t, found)
+}
+
+func TestDiskStore(t *testing.T) {
+	store, err := cache.NewDiskStore(t.TempDir())
+	require.NoError(t, err)
+
+	_, found := store.Get("GET https://example.com/nodes")
+	require.False(t, found)
+
+	store.Set("GET https://example.com/nodes", []byte("entry"))
+	store.Set("GET https://example.com/nodes", []byte("replaced"))
+
+	value, found := store.Get("GET https://example.com/nodes")
+	require.True(t, found)
+	require.Equal(t, []byte("replaced"), value)
+
+	store.Delete("GET https://example.com/nodes")
+
+	_, found = store.Get("GET https://example.com/nodes")
+	require.False(t, found)
+}
@@ -0,0 +1,244 @@
+package client_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client"
+	"github.com/SKF/go-rest-utility/client/cache"
+)
+
+func newCacheableHTTPServer(cacheControl string) (*httptest.Server, *atomic.Int32, *atomic.Int32) {
+	requests, notModified := new(atomic.Int32), new(atomic.Int32)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		w.Header().Set(headers.CacheControl, cacheControl)
+		w.Header().Set(headers.ETag, `"v1"`)
+		w.Header().Set(headers.Vary, headers.AcceptLanguage)
+
+		if r.Method == http.MethodGet && r.Header.Get(headers.IfNoneMatch) == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Write([]byte(r.Method + " " + r.Header.Get(headers.AcceptLanguage))) //nolint: errcheck
+	}))
+
+	return srv, requests, notModified
+}
+
+func getBody(t *testing.T, c *client.Client, request *client.Request) string {
+	t.Helper()
+
+	response, err := c.Do(context.Background(), request)
+	require.NoError(t, err)
+
+	defer response.Close()
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func TestClientCache_FreshResponse(t *testing.T) {
+	srv, requests, _ := newCacheableHTTPServer("max-age=60")
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithCache(cache.NewMemoryStore(10)),
+	)
+
+	require.Equal(t, "GET ", getBody(t, c, client.Get("metadata")))
+	require.Equal(t, "GET ", getBody(t, c, client.Get("metadata")))
+	require.Equal(t, int32(1), requests.Load())
+
+	require.Equal(t, "GET sv", getBody(t, c, client.Get("metadata").SetHeader(headers.AcceptLanguage, "sv")))
+	require.Equal(t, int32(2), requests.Load())
+
+	require.Equal(t, "GET ", getBody(t, c, client.Get("metadata").SetHeader(headers.CacheControl, "no-cache")))
+	require.Equal(t, int32(3), requests.Load())
+}
+
+func TestClientCache_Revalidation(t *testing.T) {
+	srv, requests, notModified := newCacheableHTTPServer("no-cache")
+	defer srv.Close()
+
+	store, err := cache.NewDiskStore(t.TempDir())
+	require.NoError(t, err)
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithCache(store),
+	)
+
+	require.Equal(t, "GET ", getBody(t, c, client.Get("metadata")))
+	require.Equal(t, "GET ", getBody(t, c, client.Get("metadata")))
+	require.Equal(t, int32(2), requests.Load())
+	require.Equal(t, int32(1), notModified.Load())
+}
+
+func TestClientCache_NoStore(t *testing.T) {
+	srv, requests, _ := newCacheableHTTPServer("no-store")
+	defer srv.Close()
+
+	store := cache.NewMemoryStore(10)
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithCache(store),
+	)
+
+	getBody(t, c, client.Get("metadata"))
+	getBody(t, c, client.Get("metadata"))
+
+	require.Equal(t, int32(2), requests.Load())
+	require.Equal(t, 0, store.Len())
+}
+
+func TestClientCache_InvalidatedByUnsafeRequest(t *testing.T) {
+	srv, requests, _ := newCacheableHTTPServer("max-age=60")
+	defer srv.Close()
+
+	store := cache.NewMemoryStore(10)
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithCache(store),
+	)
+
+	getBody(t, c, client.Get("metadata"))
+	require.Equal(t, 1, store.Len())
+
+	require.Equal(t, "PUT ", getBody(t, c, client.Put("metadata")))
+	require.Equal(t, 0, store.Len())
+
+	getBody(t, c, client.Get("metadata"))
+	require.Equal(t, int32(3), requests.Load())
+}
+
+func TestClientCache_InvalidatedByFailedPrecondition(t *testing.T) {
+	srv, _ := newVersionedHTTPServer(0)
+	defer srv.Close()
+
+	store := cache.NewMemoryStore(10)
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithCache(store),
+	)
+
+	getBody(t, c, client.Get("node"))
+	require.Equal(t, 1, store.Len())
+
+	_, err := c.Do(context.Background(), client.Put("node").IfMatch(`"outdated"`).WithJSONPayload(versionedNode{}))
+	require.ErrorIs(t, err, client.ErrPreconditionFailed)
+	require.Equal(t, 0, store.Len())
+}
+
+func TestClientCache_KeptApartPerAuthorization(t *testing.T) {
+	srv, requests, _ := newCacheableHTTPServer("max-age=60")
+	defer srv.Close()
+
+	store := cache.NewMemoryStore(10)
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithCache(store),
+	)
+
+	getBody(t, c, client.Get("metadata").SetHeader(headers.Authorization, "Bearer operator"))
+	getBody(t, c, client.Get("metadata").SetHeader(headers.Authorization, "Bearer admin"))
+	getBody(t, c, client.Get("metadata").SetHeader(headers.Authorization, "Bearer operator"))
+
+	require.Equal(t, int32(2), requests.Load())
+	require.Equal(t, 2, store.Len())
+}
+
+func TestClientCache_LargeResponse(t *testing.T) {
+	content := strings.Repeat("x", client.MaxCachedBodySize+1)
+	requests := new(atomic.Int32)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+
+		w.Header().Set(headers.CacheControl, "max-age=60")
+		w.Header().Set(headers.ETag, `"v1"`)
+		w.Write([]byte(content)) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	store := cache.NewMemoryStore(10)
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithCache(store),
+	)
+
+	require.Equal(t, content, getBody(t, c, client.Get("firmware")))
+	require.Equal(t, content, getBody(t, c, client.Get("firmware")))
+
+	require.Equal(t, int32(2), requests.Load())
+	require.Equal(t, 0, store.Len())
+}
+
+func TestClientCache_RangeRequest(t *testing.T) {
+	srv, requests, _ := newCacheableHTTPServer("max-age=60")
+	defer srv.Close()
+
+	store := cache.NewMemoryStore(10)
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithCache(store),
+	)
+
+	getBody(t, c, client.Get("metadata").SetHeader(headers.Range, "bytes=0-"))
+	getBody(t, c, client.Get("metadata").SetHeader(headers.Range, "bytes=0-"))
+
+	require.Equal(t, int32(2), requests.Load())
+	require.Equal(t, 0, store.Len())
+}
+
+func TestClientCache_Streams(t *testing.T) {
+	sse := newEndlessHTTPServer(t, "text/event-stream", "data: first\n\n", ": keep-alive\n\n")
+	ndjson := newEndlessHTTPServer(t, "application/x-ndjson", "{\"x\": 1}\n", "{\"x\": 2}\n")
+
+	requireReturns(t, func() {
+		c := client.NewClient(client.WithBaseURL(sse.URL), client.WithCache(cache.NewMemoryStore(10)))
+
+		for event, err := range c.Subscribe(context.Background(), client.Get("events")) {
+			require.NoError(t, err)
+			require.Equal(t, "first", event.Data)
+
+			break
+		}
+	})
+
+	requireReturns(t, func() {
+		c := client.NewClient(client.WithBaseURL(ndjson.URL), client.WithCache(cache.NewMemoryStore(10)))
+
+		response, err := c.Do(context.Background(), client.Get("points"))
+		require.NoError(t, err)
+
+		for p, err := range client.StreamNDJSON[point](response) {
+			require.NoError(t, err)
+			require.Equal(t, point{1}, p)
+
+			break
+		}
+	})
+}
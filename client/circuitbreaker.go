@@ -0,0 +1,291 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of a circuit in a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets all requests through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects all requests with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of trial requests through to
+	// decide whether to close or open the circuit again.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	DefaultCircuitFailureThreshold = 5
+	DefaultCircuitOpenTimeout      = 30 * time.Second
+)
+
+// CircuitKey decides which circuit of a CircuitBreaker a request belongs to.
+type CircuitKey func(req *http.Request) string
+
+// CircuitKeyByHost has a circuit per host, which is the default.
+func CircuitKeyByHost(req *http.Request) string {
+	return req.URL.Host
+}
+
+// CircuitKeyByURITemplate has a circuit per host and URI template, such that
+// a failing endpoint does not affect the other endpoints of the host.
+func CircuitKeyByURITemplate(req *http.Request) string {
+	uriTemplate, _ := URITemplateFromContext(req.Context())
+	return req.URL.Host + " " + uriTemplate
+}
+
+// CircuitBreakerConfig configures the thresholds of a CircuitBreaker.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures which opens a
+	// circuit, defaults to DefaultCircuitFailureThreshold.
+	FailureThreshold int
+	// OpenTimeout is how long a circuit stays open before trial requests are
+	// let through, defaults to DefaultCircuitOpenTimeout.
+	OpenTimeout time.Duration
+	// HalfOpenRequests is the number of concurrent trial requests let through
+	// a half-open circuit, defaults to 1.
+	HalfOpenRequests int
+
+	// Key decides the circuit of a request, defaults to CircuitKeyByHost.
+	Key CircuitKey
+	// IsFailure decides whether the outcome of a request is a failure,
+	// defaults to errors and 5xx responses.
+	IsFailure func(resp *http.Response, err error) bool
+	// OnStateChange is called whenever a circuit changes state.
+	OnStateChange func(key string, from, to CircuitState)
+}
+
+// CircuitBreaker rejects requests with ErrCircuitOpen, without sending them,
+// while their downstream service is failing.
+type CircuitBreaker struct {
+	config CircuitBreakerConfig
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	trials   int
+}
+
+type stateChange struct {
+	key      string
+	from, to CircuitState
+}
+
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultCircuitFailureThreshold
+	}
+
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = DefaultCircuitOpenTimeout
+	}
+
+	if config.HalfOpenRequests <= 0 {
+		config.HalfOpenRequests = 1
+	}
+
+	if config.Key == nil {
+		config.Key = CircuitKeyByHost
+	}
+
+	if config.IsFailure == nil {
+		config.IsFailure = isServerFailure
+	}
+
+	return &CircuitBreaker{
+		config:   config,
+		circuits: make(map[string]*circuit),
+	}
+}
+
+func isServerFailure(resp *http.Response, err error) bool {
+	return err != nil || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// State returns the current state of the circuit with the key.
+func (cb *CircuitBreaker) State(key string) CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if c, exists := cb.circuits[key]; exists {
+		if c.state == CircuitOpen && time.Since(c.openedAt) >= cb.config.OpenTimeout {
+			return CircuitHalfOpen
+		}
+
+		return c.state
+	}
+
+	return CircuitClosed
+}
+
+// States returns the current state of all circuits which have seen requests.
+func (cb *CircuitBreaker) States() map[string]CircuitState {
+	cb.mu.Lock()
+	keys := make([]string, 0, len(cb.circuits))
+
+	for key := range cb.circuits {
+		keys = append(keys, key)
+	}
+	cb.mu.Unlock()
+
+	states := make(map[string]CircuitState, len(keys))
+	for _, key := range keys {
+		states[key] = cb.State(key)
+	}
+
+	return states
+}
+
+// Middleware returns a Middleware guarding the requests with the CircuitBreaker.
+func (cb *CircuitBreaker) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			key := cb.config.Key(req)
+
+			trial, allowed := cb.allow(key, time.Now())
+			if !allowed {
+				closeRequestBody(req)
+				return nil, ErrCircuitOpen
+			}
+
+			resp, err := next.RoundTrip(req)
+
+			// A request cancelled by the caller tells nothing about the downstream
+			// service, unlike one which timed out.
+			if errors.Is(req.Context().Err(), context.Canceled) {
+				cb.release(key, trial)
+				return resp, err
+			}
+
+			cb.record(key, trial, cb.config.IsFailure(resp, err), time.Now())
+
+			return resp, err
+		})
+	}
+}
+
+// allow reports whether a request may be sent and whether it is a trial
+// request of a half-open circuit.
+func (cb *CircuitBreaker) allow(key string, now time.Time) (trial, allowed bool) {
+	var changes []stateChange
+
+	defer func() { cb.notify(changes) }()
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c, exists := cb.circuits[key]
+	if !exists {
+		c = &circuit{state: CircuitClosed}
+		cb.circuits[key] = c
+	}
+
+	if c.state == CircuitOpen && now.Sub(c.openedAt) >= cb.config.OpenTimeout {
+		changes = append(changes, c.transition(key, CircuitHalfOpen, now))
+	}
+
+	switch c.state {
+	case CircuitClosed:
+		return false, true
+	case CircuitHalfOpen:
+		if c.trials < cb.config.HalfOpenRequests {
+			c.trials++
+			return true, true
+		}
+
+		return false, false
+	default:
+		return false, false
+	}
+}
+
+// release gives back the trial slot of a request which ended without telling
+// anything about the downstream service, leaving the circuit as it is.
+func (cb *CircuitBreaker) release(key string, trial bool) {
+	if !trial {
+		return
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.circuits[key].trials--
+}
+
+func (cb *CircuitBreaker) record(key string, trial, failure bool, now time.Time) {
+	var changes []stateChange
+
+	defer func() { cb.notify(changes) }()
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c := cb.circuits[key]
+
+	if trial {
+		c.trials--
+	}
+
+	switch {
+	case c.state == CircuitHalfOpen && trial && failure:
+		changes = append(changes, c.transition(key, CircuitOpen, now))
+	case c.state == CircuitHalfOpen && trial:
+		changes = append(changes, c.transition(key, CircuitClosed, now))
+	case c.state == CircuitClosed && failure:
+		if c.failures++; c.failures >= cb.config.FailureThreshold {
+			changes = append(changes, c.transition(key, CircuitOpen, now))
+		}
+	case c.state == CircuitClosed:
+		c.failures = 0
+	}
+}
+
+func (c *circuit) transition(key string, to CircuitState, now time.Time) stateChange {
+	change := stateChange{key: key, from: c.state, to: to}
+
+	c.state = to
+	c.failures = 0
+
+	if to == CircuitOpen {
+		c.openedAt = now
+	}
+
+	return change
+}
+
+func (cb *CircuitBreaker) notify(changes []stateChange) {
+	if cb.config.OnStateChange == nil {
+		return
+	}
+
+	for _, change := range changes {
+		cb.config.OnStateChange(change.key, change.from, change.to)
+	}
+}
@@ -0,0 +1,228 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client"
+	"github.com/SKF/go-rest-utility/client/retry"
+)
+
+func TestClientCircuitBreaker(t *testing.T) {
+	var (
+		healthy  atomic.Bool
+		requests atomic.Int32
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var changes []string
+
+	breaker := client.NewCircuitBreaker(client.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      50 * time.Millisecond,
+		OnStateChange: func(_ string, from, to client.CircuitState) {
+			changes = append(changes, from.String()+"->"+to.String())
+		},
+	})
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithCircuitBreaker(breaker),
+	)
+
+	serverURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	for range 2 {
+		_, err = c.Do(context.Background(), client.Get("nodes"))
+		require.ErrorIs(t, err, client.ErrInternalServerError)
+	}
+
+	require.Equal(t, client.CircuitOpen, breaker.State(serverURL.Host))
+
+	_, err = c.Do(context.Background(), client.Get("nodes"))
+	require.ErrorIs(t, err, client.ErrCircuitOpen)
+	require.EqualValues(t, 2, requests.Load())
+
+	healthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+
+	require.Equal(t, client.CircuitHalfOpen, breaker.State(serverURL.Host))
+
+	response, err := c.Do(context.Background(), client.Get("nodes"))
+	require.NoError(t, err)
+	require.NoError(t, response.Close())
+
+	require.Equal(t, client.CircuitClosed, breaker.State(serverURL.Host))
+	require.Equal(t, map[string]client.CircuitState{serverURL.Host: client.CircuitClosed}, breaker.States())
+	require.Equal(t, []string{"closed->open", "open->half-open", "half-open->closed"}, changes)
+}
+
+func TestClientCircuitBreaker_KeyByURITemplate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/failing" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	breaker := client.NewCircuitBreaker(client.CircuitBreakerConfig{
+		FailureThreshold: 1,
+		Key:              client.CircuitKeyByURITemplate,
+	})
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithCircuitBreaker(breaker),
+	)
+
+	_, err := c.Do(context.Background(), client.Get("failing"))
+	require.ErrorIs(t, err, client.ErrServiceUnavailable)
+
+	_, err = c.Do(context.Background(), client.Get("failing"))
+	require.ErrorIs(t, err, client.ErrCircuitOpen)
+
+	response, err := c.Do(context.Background(), client.Get("working"))
+	require.NoError(t, err)
+	require.NoError(t, response.Close())
+}
+
+func TestClientCircuitBreaker_NotRetried(t *testing.T) {
+	srv, attempts := newFlakyHTTPServer(10, http.StatusServiceUnavailable)
+	defer srv.Close()
+
+	breaker := client.NewCircuitBreaker(client.CircuitBreakerConfig{FailureThreshold: 1})
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithCircuitBreaker(breaker),
+		client.WithRetry(client.RetryPolicy{
+			Backoff: &retry.ExponentialJitterBackoff{MaxAttempts: 5},
+		}),
+	)
+
+	_, err := c.Do(context.Background(), client.Get("nodes"))
+	require.ErrorIs(t, err, client.ErrCircuitOpen)
+	require.Equal(t, int32(1), attempts.Load())
+}
+
+func TestClientCircuitBreaker_Timeouts(t *testing.T) {
+	var requests atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	breaker := client.NewCircuitBreaker(client.CircuitBreakerConfig{FailureThreshold: 2})
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithTimeout(20*time.Millisecond),
+		client.WithCircuitBreaker(breaker),
+	)
+
+	for range 2 {
+		_, err := c.Do(context.Background(), client.Get("nodes"))
+		require.Error(t, err)
+		require.NotErrorIs(t, err, client.ErrCircuitOpen)
+	}
+
+	_, err := c.Do(context.Background(), client.Get("nodes"))
+	require.ErrorIs(t, err, client.ErrCircuitOpen)
+	require.EqualValues(t, 2, requests.Load())
+
+	serverURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, client.CircuitOpen, breaker.State(serverURL.Host))
+}
+
+func TestClientCircuitBreaker_CancelledNotCounted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	breaker := client.NewCircuitBreaker(client.CircuitBreakerConfig{FailureThreshold: 2})
+
+	c := client.NewClient(client.WithBaseURL(srv.URL), client.WithCircuitBreaker(breaker))
+
+	for range 3 {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		_, err := c.Do(ctx, client.Get("nodes"))
+		require.ErrorIs(t, err, context.Canceled)
+	}
+
+	serverURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	require.Equal(t, client.CircuitClosed, breaker.State(serverURL.Host))
+}
+
+func TestClientCircuitBreaker_CancelledTrial(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-r.Context().Done()
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	breaker := client.NewCircuitBreaker(client.CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      20 * time.Millisecond,
+	})
+
+	c := client.NewClient(client.WithBaseURL(srv.URL), client.WithCircuitBreaker(breaker))
+
+	serverURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	_, err = c.Do(context.Background(), client.Get("nodes"))
+	require.ErrorIs(t, err, client.ErrInternalServerError)
+	require.Equal(t, client.CircuitOpen, breaker.State(serverURL.Host))
+
+	time.Sleep(30 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err = c.Do(ctx, client.Get("slow"))
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, client.CircuitHalfOpen, breaker.State(serverURL.Host))
+
+	_, err = c.Do(context.Background(), client.Get("nodes"))
+	require.ErrorIs(t, err, client.ErrInternalServerError)
+	require.Equal(t, client.CircuitOpen, breaker.State(serverURL.Host))
+}
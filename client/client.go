@@ -1,19 +1,28 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/go-http-utils/headers"
 
 	"github.com/SKF/go-rest-utility/client/auth"
+	"github.com/SKF/go-rest-utility/client/codec"
 	"github.com/SKF/go-rest-utility/problems"
 )
 
 const (
-	DefaultUserAgent      string = "go-rest-utility/v1"
+	DefaultUserAgent string = "go-rest-utility/v1"
+
+	// Deprecated: The client advertises every registered content coding,
+	// see AcceptEncoding.
 	DefaultAcceptEncoding string = "gzip"
 )
 
@@ -21,8 +30,19 @@ type Client struct {
 	BaseURL        *url.URL
 	TokenProvider  auth.TokenProvider
 	problemDecoder ProblemDecoder
+	retryPolicy    *RetryPolicy
+
+	maxErrorBodySize    int64
+	maxDecompressedSize int64
+	resourceNamer       ResourceNamer
+	codecs              *codec.Registry
+
+	requestCompression RequestCompression
+	uncompressedHosts  sync.Map
 
 	client         *http.Client
+	transport      http.RoundTripper
+	middlewares    []Middleware
 	defaultHeaders http.Header
 }
 
@@ -31,37 +51,101 @@ func NewClient(opts ...Option) *Client {
 	client := &Client{
 		BaseURL:        nil,
 		TokenProvider:  nil,
-		problemDecoder: nil,
-		client:         new(http.Client),
-		defaultHeaders: make(http.Header),
+		problemDecoder: NewProblemRegistry(),
+		retryPolicy:    nil,
+
+		maxErrorBodySize:    DefaultMaxErrorBodySize,
+		maxDecompressedSize: DefaultMaxDecompressedSize,
+		resourceNamer:       DefaultResourceNamer,
+		codecs:              defaultCodecs,
+		client:              new(http.Client),
+		defaultHeaders:      make(http.Header),
 	}
 
 	client.client.CheckRedirect = redirectHandler
 
 	client.defaultHeaders.Set(headers.UserAgent, DefaultUserAgent)
-	client.defaultHeaders.Set(headers.AcceptEncoding, DefaultAcceptEncoding)
+	client.defaultHeaders.Set(headers.AcceptEncoding, AcceptEncoding())
 
 	for _, opt := range opts {
 		opt(client)
 	}
 
+	if client.defaultHeaders.Get(headers.Accept) == "" {
+		client.defaultHeaders.Set(headers.Accept, acceptHeader(client.codecs.MediaTypes()))
+	}
+
+	client.client.Transport = chainMiddlewares(
+		client.transport,
+		append(client.middlewares, client.authorize),
+	)
+
 	return client
 }
 
+// acceptHeader lists the media types of the codecs for the Accept header,
+// preferring JSON, or the first media type without JSON, over the others.
+func acceptHeader(mediaTypes []string) string {
+	if len(mediaTypes) == 0 {
+		return ""
+	}
+
+	preferred := codec.JSONMediaType
+	if !slices.Contains(mediaTypes, preferred) {
+		preferred = mediaTypes[0]
+	}
+
+	accepted := []string{preferred}
+
+	for _, mediaType := range mediaTypes {
+		if mediaType != preferred {
+			accepted = append(accepted, mediaType+";q=0.9")
+		}
+	}
+
+	return strings.Join(accepted, ", ")
+}
+
+// authorize is the innermost Middleware of every Client, authorizing the
+// requests with the current TokenProvider of the Client.
+func (c *Client) authorize(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return TokenMiddleware(c.TokenProvider)(next).RoundTrip(req)
+	})
+}
+
 // Do Executes the http request, don't forget to
 // call response.Close() if no error is returned
 func (c *Client) Do(ctx context.Context, r *Request) (*Response, error) {
+	do := c.doOnce
+	if c.shouldRetry(r) {
+		do = c.doWithRetry
+	}
+
+	httpResponse, err := do(ctx, r) //nolint: bodyclose
+	if err != nil {
+		return nil, err
+	}
+
+	return c.prepareResponse(ctx, httpResponse)
+}
+
+func (c *Client) doOnce(ctx context.Context, r *Request) (*http.Response, error) {
 	httpRequest, err := c.prepareRequest(ctx, r)
 	if err != nil {
 		return nil, err
 	}
 
+	return c.sendWithFallback(ctx, r, httpRequest)
+}
+
+func (c *Client) send(httpRequest *http.Request) (*http.Response, error) {
 	httpResponse, err := c.client.Do(httpRequest) //nolint: bodyclose
 	if err != nil {
 		return nil, fmt.Errorf("unable to perform http request: %w", err)
 	}
 
-	return c.prepareResponse(ctx, httpResponse)
+	return httpResponse, nil
 }
 
 func (c *Client) DoAndUnmarshal(ctx context.Context, r *Request, v interface{}) error {
@@ -86,51 +170,107 @@ func (c *Client) prepareRequest(ctx context.Context, req *Request) (*http.Reques
 	}
 
 	ctx = context.WithValue(ctx, followRedirectsKey, req.followRedirects)
+	ctx = context.WithValue(ctx, uriTemplateKey, req.uriTemplate)
+	ctx = context.WithValue(ctx, resourceNamerKey, c.resourceNamer)
+	ctx = context.WithValue(ctx, codecsKey, c.codecs)
 
-	httpRequest, err := http.NewRequestWithContext(ctx, req.method, url.String(), req.body)
+	requestPayload := req.body
+	if encoding, ok := requestPayload.(encodingPayload); ok {
+		if requestPayload, err = encoding.encode(c.codecs); err != nil {
+			return nil, fmt.Errorf("unable to encode request body: %w", err)
+		}
+	}
+
+	if _, empty := requestPayload.(noPayload); req.progress != nil && !empty {
+		requestPayload = progressPayload{payload: requestPayload, fn: req.progress}
+	}
+
+	body, err := requestPayload.open()
+	if err != nil {
+		return nil, fmt.Errorf("unable to open request body: %w", err)
+	}
+
+	encoding, compress := c.compression(req, url.Host, requestPayload.contentLength())
+	if compress {
+		compressed, err := newCompressedPayload(requestPayload, encoding)
+		if err != nil {
+			body.Close() //nolint: errcheck
+			return nil, err
+		}
+
+		body = compressed.compress(body)
+		requestPayload = compressed
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, req.method, url.String(), body)
 	if err != nil {
+		body.Close() //nolint: errcheck
 		return nil, fmt.Errorf("unable to create http request: %w", err)
 	}
 
+	if length := requestPayload.contentLength(); length >= 0 {
+		httpRequest.ContentLength = length
+	}
+
+	if requestPayload.replayable() {
+		httpRequest.GetBody = requestPayload.open
+	}
+
+	httpRequest.Header = req.header.Clone()
+
 	for header, defaultValue := range c.defaultHeaders {
-		if _, exists := req.header[header]; !exists {
-			req.header[header] = defaultValue
+		if _, exists := httpRequest.Header[header]; !exists {
+			httpRequest.Header[header] = slices.Clone(defaultValue)
 		}
 	}
 
-	if c.TokenProvider != nil {
-		token, err := c.TokenProvider.GetRawToken(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("unable to get token: %w", err)
-		}
+	if compress {
+		httpRequest.Header.Set(headers.ContentEncoding, encoding)
+	}
+
+	return httpRequest, nil
+}
 
-		req.header.Set(headers.Authorization, token.String())
+// decodeProblem decodes the problem of the response, or returns nil if the
+// body is not a valid problem. The body is then left for the HTTPError.
+func (c *Client) decodeProblem(ctx context.Context, resp *http.Response) problems.Problem {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close() //nolint: errcheck
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return nil
 	}
 
-	httpRequest.Header = req.header
+	decoded := *resp
+	decoded.Body = io.NopCloser(bytes.NewReader(body))
 
-	return httpRequest, nil
+	problem, err := c.problemDecoder.DecodeProblem(ctx, &decoded)
+	if err != nil {
+		return nil
+	}
+
+	return problem
 }
 
 func (c *Client) prepareResponse(ctx context.Context, resp *http.Response) (*Response, error) {
 	var err error
-	if resp.Body, resp.Header, err = DecompressResponse(*resp); err != nil {
+	body := resp.Body
+	if resp.Body, resp.Header, err = decompressResponse(*resp, c.maxDecompressedSize); err != nil {
+		body.Close() //nolint: errcheck
 		return nil, fmt.Errorf("failed to decompress response: %w", err)
 	}
 
-	if c.problemDecoder != nil && resp.Header.Get(headers.ContentType) == problems.ContentType {
-		problem, err := c.problemDecoder.DecodeProblem(ctx, resp)
-		if err != nil {
-			return nil, fmt.Errorf("unable to decode http error into problem: %w", err)
+	if _, isProblem := problemMediaType(resp.Header); c.problemDecoder != nil && isProblem {
+		if problem := c.decodeProblem(ctx, resp); problem != nil {
+			return nil, problem
 		}
-
-		return nil, problem
 	}
 
 	if resp.StatusCode >= http.StatusBadRequest {
 		return nil, newHTTPError(resp.StatusCode).
-			withInstance(resp.Request.URL.String()).
-			withBody(resp.Body)
+			withResponse(resp).
+			withBody(resp.Body, c.maxErrorBodySize)
 	}
 
 	return &Response{*resp}, nil
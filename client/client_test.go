@@ -45,7 +45,7 @@ func TestClientGet(t *testing.T) {
 	require.Equal(t, "/endpoint", echo.URL)
 	require.Equal(t, http.MethodGet, echo.Method)
 	require.Equal(t, DefaultUserAgent, echo.Header.Get(headers.UserAgent))
-	require.Equal(t, DefaultAcceptEncoding, echo.Header.Get(headers.AcceptEncoding))
+	require.Equal(t, AcceptEncoding(), echo.Header.Get(headers.AcceptEncoding))
 }
 
 func TestClientPut(t *testing.T) {
@@ -75,7 +75,7 @@ func TestClientPut(t *testing.T) {
 	require.Equal(t, "/transfer/", echo.URL)
 	require.Equal(t, http.MethodPut, echo.Method)
 	require.Equal(t, DefaultUserAgent, echo.Header.Get(headers.UserAgent))
-	require.Equal(t, DefaultAcceptEncoding, echo.Header.Get(headers.AcceptEncoding))
+	require.Equal(t, AcceptEncoding(), echo.Header.Get(headers.AcceptEncoding))
 	require.Equal(t, "application/json", echo.Header.Get(headers.ContentType))
 
 	require.NotNil(t, echo.Body)
@@ -109,7 +109,7 @@ func TestClientDefaultHeader(t *testing.T) {
 	require.Equal(t, "/endpoint", echo.URL)
 	require.Equal(t, http.MethodGet, echo.Method)
 	require.Equal(t, "Custom", echo.Header.Get(headers.UserAgent))
-	require.Equal(t, DefaultAcceptEncoding, echo.Header.Get(headers.AcceptEncoding))
+	require.Equal(t, AcceptEncoding(), echo.Header.Get(headers.AcceptEncoding))
 	require.Equal(t, "78147f11-62d9-4af0-917d-a0eb26d1c1fc", echo.Header.Get("X-Client-ID"))
 }
 
@@ -0,0 +1,60 @@
+// Package codec encodes and decodes request and response bodies by media
+// type.
+package codec
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	JSONMediaType    = "application/json"
+	XMLMediaType     = "application/xml"
+	YAMLMediaType    = "application/yaml"
+	CBORMediaType    = "application/cbor"
+	MsgPackMediaType = "application/vnd.msgpack"
+)
+
+// Codec encodes values into, and decodes values from, a body of a media type.
+type Codec interface {
+	Encode(w io.Writer, v interface{}) error
+	Decode(r io.Reader, v interface{}) error
+}
+
+type JSON struct{}
+
+func (JSON) Encode(w io.Writer, v interface{}) error { return json.NewEncoder(w).Encode(v) }
+func (JSON) Decode(r io.Reader, v interface{}) error { return json.NewDecoder(r).Decode(v) }
+
+type XML struct{}
+
+func (XML) Encode(w io.Writer, v interface{}) error { return xml.NewEncoder(w).Encode(v) }
+func (XML) Decode(r io.Reader, v interface{}) error { return xml.NewDecoder(r).Decode(v) }
+
+type YAML struct{}
+
+func (YAML) Encode(w io.Writer, v interface{}) error {
+	encoder := yaml.NewEncoder(w)
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+func (YAML) Decode(r io.Reader, v interface{}) error { return yaml.NewDecoder(r).Decode(v) }
+
+type CBOR struct{}
+
+func (CBOR) Encode(w io.Writer, v interface{}) error { return cbor.NewEncoder(w).Encode(v) }
+func (CBOR) Decode(r io.Reader, v interface{}) error { return cbor.NewDecoder(r).Decode(v) }
+
+type MsgPack struct{}
+
+func (MsgPack) Encode(w io.Writer, v interface{}) error { return msgpack.NewEncoder(w).Encode(v) }
+func (MsgPack) Decode(r io.Reader, v interface{}) error { return msgpack.NewDecoder(r).Decode(v) }
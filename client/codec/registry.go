@@ -0,0 +1,86 @@
+package codec
+
+import (
+	"mime"
+	"strings"
+	"sync"
+)
+
+// Registry maps media types to the Codec of their bodies.
+type Registry struct {
+	mu         sync.RWMutex
+	codecs     map[string]Codec
+	mediaTypes []string
+}
+
+// NewRegistry creates a Registry with the JSON, XML, YAML, CBOR and
+// MessagePack codecs registered, in that order of preference.
+func NewRegistry() *Registry {
+	return new(Registry).
+		Register(JSONMediaType, JSON{}).
+		Register(XMLMediaType, XML{}, "text/xml").
+		Register(YAMLMediaType, YAML{}, "application/x-yaml", "text/yaml").
+		Register(CBORMediaType, CBOR{}).
+		Register(MsgPackMediaType, MsgPack{}, "application/msgpack", "application/x-msgpack")
+}
+
+// Register the codec for the media type and its aliases, replacing any
+// codec already registered for them. Only the media type is advertised by
+// MediaTypes, the aliases are only used to look up the codec of a body.
+func (r *Registry) Register(mediaType string, codec Codec, aliases ...string) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.codecs == nil {
+		r.codecs = make(map[string]Codec)
+	}
+
+	mediaType = strings.ToLower(mediaType)
+
+	if _, exists := r.codecs[mediaType]; !exists {
+		r.mediaTypes = append(r.mediaTypes, mediaType)
+	}
+
+	r.codecs[mediaType] = codec
+
+	for _, alias := range aliases {
+		r.codecs[strings.ToLower(alias)] = codec
+	}
+
+	return r
+}
+
+// Lookup returns the codec of the media type, which may be a full Content-Type
+// with parameters. Media types with a structured syntax suffix, such as
+// `application/vnd.skf+json`, fall back to the codec of the suffix.
+func (r *Registry) Lookup(mediaType string) (Codec, bool) {
+	if parsed, _, err := mime.ParseMediaType(mediaType); err == nil {
+		mediaType = parsed
+	}
+
+	mediaType = strings.ToLower(mediaType)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if codec, exists := r.codecs[mediaType]; exists {
+		return codec, true
+	}
+
+	if _, suffix, found := strings.Cut(mediaType, "+"); found {
+		if codec, exists := r.codecs["application/"+suffix]; exists {
+			return codec, true
+		}
+	}
+
+	return nil, false
+}
+
+// MediaTypes returns the registered media types in order of registration,
+// excluding aliases.
+func (r *Registry) MediaTypes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]string(nil), r.mediaTypes...)
+}
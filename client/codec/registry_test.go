@@ -0,0 +1,67 @@
+package codec_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client/codec"
+)
+
+type node struct {
+	ID    string `json:"id" xml:"id" yaml:"id" cbor:"id" msgpack:"id"`
+	Label string `json:"label" xml:"label" yaml:"label" cbor:"label" msgpack:"label"`
+}
+
+func TestRegistry_RoundTrip(t *testing.T) {
+	registry := codec.NewRegistry()
+
+	for _, mediaType := range registry.MediaTypes() {
+		t.Run(mediaType, func(t *testing.T) {
+			c, found := registry.Lookup(mediaType)
+			require.True(t, found)
+
+			buf := new(bytes.Buffer)
+			require.NoError(t, c.Encode(buf, node{ID: "42", Label: "pump"}))
+
+			var decoded node
+			require.NoError(t, c.Decode(buf, &decoded))
+			require.Equal(t, node{ID: "42", Label: "pump"}, decoded)
+		})
+	}
+}
+
+func TestRegistry_Lookup(t *testing.T) {
+	registry := codec.NewRegistry()
+
+	require.Equal(t, []string{
+		codec.JSONMediaType,
+		codec.XMLMediaType,
+		codec.YAMLMediaType,
+		codec.CBORMediaType,
+		codec.MsgPackMediaType,
+	}, registry.MediaTypes())
+
+	for mediaType, expected := range map[string]codec.Codec{
+		"application/json; charset=utf-8": codec.JSON{},
+		"Application/JSON":                codec.JSON{},
+		"application/vnd.skf.node+json":   codec.JSON{},
+		"text/xml":                        codec.XML{},
+		"application/x-yaml":              codec.YAML{},
+		"application/msgpack":             codec.MsgPack{},
+	} {
+		c, found := registry.Lookup(mediaType)
+		require.True(t, found, mediaType)
+		require.Equal(t, expected, c, mediaType)
+	}
+
+	_, found := registry.Lookup("text/plain")
+	require.False(t, found)
+
+	registry.Register("text/plain", codec.JSON{})
+
+	_, found = registry.Lookup("text/plain")
+	require.True(t, found)
+	require.Contains(t, registry.MediaTypes(), "text/plain")
+}
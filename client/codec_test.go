@@ -0,0 +1,103 @@
+package client_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client"
+	"github.com/SKF/go-rest-utility/client/codec"
+)
+
+type codecNode struct {
+	ID    string `yaml:"id" msgpack:"id"`
+	Label string `yaml:"label" msgpack:"label"`
+}
+
+// newMirrorHTTPServer responds with the request body and content type, and
+// the Accept header of the request.
+func newMirrorHTTPServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headers.ContentType, r.Header.Get(headers.ContentType))
+		w.Header().Set("X-Accept", r.Header.Get(headers.Accept))
+
+		io.Copy(w, r.Body) //nolint: errcheck
+	}))
+}
+
+func TestRequestWithPayloadAs(t *testing.T) {
+	srv := newMirrorHTTPServer()
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	for _, mediaType := range []string{"application/yaml", "application/vnd.msgpack", "application/cbor"} {
+		t.Run(mediaType, func(t *testing.T) {
+			response, err := c.Do(context.Background(), client.Post("nodes").WithPayloadAs(mediaType, codecNode{ID: "42", Label: "pump"}))
+			require.NoError(t, err)
+
+			require.Equal(t, mediaType, response.Header.Get(headers.ContentType))
+
+			var node codecNode
+			require.NoError(t, response.Unmarshal(&node))
+			require.Equal(t, codecNode{ID: "42", Label: "pump"}, node)
+		})
+	}
+}
+
+func TestRequestWithPayloadAs_UnknownMediaType(t *testing.T) {
+	srv := newMirrorHTTPServer()
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	_, err := c.Do(context.Background(), client.Post("nodes").WithPayloadAs("text/csv", codecNode{}))
+	require.ErrorContains(t, err, "no codec registered for text/csv")
+}
+
+func TestClientAcceptsRegisteredCodecs(t *testing.T) {
+	srv := newMirrorHTTPServer()
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	response, err := c.Do(context.Background(), client.Get("nodes"))
+	require.NoError(t, err)
+	require.NoError(t, response.Close())
+	require.Equal(t,
+		"application/json, application/xml;q=0.9, application/yaml;q=0.9, application/cbor;q=0.9, application/vnd.msgpack;q=0.9",
+		response.Header.Get("X-Accept"),
+	)
+
+	c = client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithCodecs(new(codec.Registry).Register(codec.YAMLMediaType, codec.YAML{})),
+	)
+
+	response, err = c.Do(context.Background(), client.Get("nodes"))
+	require.NoError(t, err)
+	require.NoError(t, response.Close())
+	require.Equal(t, codec.YAMLMediaType, response.Header.Get("X-Accept"))
+
+	c = client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithCodecs(new(codec.Registry).
+			Register(codec.YAMLMediaType, codec.YAML{}).
+			Register(codec.JSONMediaType, codec.JSON{})),
+	)
+
+	response, err = c.Do(context.Background(), client.Get("nodes"))
+	require.NoError(t, err)
+	require.NoError(t, response.Close())
+	require.Equal(t, "application/json, application/yaml;q=0.9", response.Header.Get("X-Accept"))
+
+	response, err = c.Do(context.Background(), client.Get("nodes").SetHeader(headers.Accept, "text/csv"))
+	require.NoError(t, err)
+	require.NoError(t, response.Close())
+	require.Equal(t, "text/csv", response.Header.Get("X-Accept"))
+}
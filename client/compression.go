@@ -0,0 +1,140 @@
+package client
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-http-utils/headers"
+	"github.com/klauspost/compress/zstd"
+)
+
+// RequestCompression configures the compression of request bodies.
+type RequestCompression struct {
+	// Encoding is the content coding to compress with, "gzip" or "zstd".
+	// Empty disables compression.
+	Encoding string
+
+	// Threshold is the size in bytes from which bodies are compressed. Bodies
+	// of unknown size are always compressed.
+	Threshold int64
+}
+
+type contentEncoder func(w io.Writer) (io.WriteCloser, error)
+
+var contentEncoders = map[string]contentEncoder{
+	"gzip": func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriter(w), nil
+	},
+	"zstd": func(w io.Writer) (io.WriteCloser, error) {
+		return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	},
+}
+
+// WithCompression overrides the RequestCompression of the Client for this
+// Request, an empty RequestCompression disables compression.
+func (r *Request) WithCompression(compression RequestCompression) *Request {
+	r.compression = &compression
+
+	return r
+}
+
+// compression returns the content coding to compress the body of the Request
+// with, if any. Bodies already encoded by the caller are left untouched, as are
+// bodies to hosts whose server has rejected compressed bodies before.
+func (c *Client) compression(r *Request, host string, length int64) (string, bool) {
+	compression := c.requestCompression
+	if r.compression != nil {
+		compression = *r.compression
+	}
+
+	if compression.Encoding == "" || length == 0 || (length > 0 && length < compression.Threshold) {
+		return "", false
+	}
+
+	if r.header.Get(headers.ContentEncoding) != "" {
+		return "", false
+	}
+
+	if _, rejected := c.uncompressedHosts.Load(host); rejected {
+		return "", false
+	}
+
+	return compression.Encoding, true
+}
+
+// compressedPayload compresses the body of the payload while it is being sent,
+// without buffering it.
+type compressedPayload struct {
+	payload payload
+	encoder contentEncoder
+}
+
+func newCompressedPayload(p payload, encoding string) (*compressedPayload, error) {
+	encoder, found := contentEncoders[encoding]
+	if !found {
+		return nil, fmt.Errorf("unsupported request content coding %q", encoding)
+	}
+
+	return &compressedPayload{payload: p, encoder: encoder}, nil
+}
+
+func (cp *compressedPayload) open() (io.ReadCloser, error) {
+	body, err := cp.payload.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return cp.compress(body), nil
+}
+
+func (cp *compressedPayload) compress(body io.ReadCloser) io.ReadCloser {
+	reader, writer := io.Pipe()
+
+	go func() {
+		defer body.Close()
+
+		compressor, err := cp.encoder(writer)
+		if err == nil {
+			_, err = io.Copy(compressor, body)
+
+			if closeErr := compressor.Close(); err == nil {
+				err = closeErr
+			}
+		}
+
+		writer.CloseWithError(err)
+	}()
+
+	return reader
+}
+
+func (cp *compressedPayload) contentLength() int64 { return -1 }
+func (cp *compressedPayload) replayable() bool     { return cp.payload.replayable() }
+
+// sendWithFallback sends the request, and if the server rejected its
+// compressed body with 415 Unsupported Media Type, sends it again without
+// compressing it. The host is remembered such that its requests are no longer
+// compressed.
+func (c *Client) sendWithFallback(ctx context.Context, r *Request, httpRequest *http.Request) (*http.Response, error) {
+	httpResponse, err := c.send(httpRequest)
+	if err != nil || httpResponse.StatusCode != http.StatusUnsupportedMediaType {
+		return httpResponse, err
+	}
+
+	compressed := httpRequest.Header.Get(headers.ContentEncoding) != "" && r.header.Get(headers.ContentEncoding) == ""
+	if !compressed || !r.body.replayable() {
+		return httpResponse, nil
+	}
+
+	discardResponse(httpResponse)
+	c.uncompressedHosts.Store(httpRequest.URL.Host, struct{}{})
+
+	if httpRequest, err = c.prepareRequest(ctx, r); err != nil {
+		return nil, err
+	}
+
+	return c.send(httpRequest)
+}
@@ -0,0 +1,154 @@
+package client_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-http-utils/headers"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client"
+	"github.com/SKF/go-rest-utility/client/retry"
+)
+
+type compressionEcho struct {
+	Encoding string
+	Body     string
+}
+
+// newDecompressingHTTPServer echoes the decompressed request body and its
+// content coding. The first failures requests are answered with status.
+func newDecompressingHTTPServer(status int, failures int32, rejectCompressed bool) (*httptest.Server, *atomic.Int32) {
+	rejected := new(atomic.Int32)
+	attempts := new(atomic.Int32)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := r.Header.Get(headers.ContentEncoding)
+
+		if rejectCompressed && encoding != "" {
+			rejected.Add(1)
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+
+			return
+		}
+
+		var body io.Reader = r.Body
+
+		switch encoding {
+		case "gzip":
+			body, _ = gzip.NewReader(r.Body) //nolint: errcheck
+		case "zstd":
+			decoder, _ := zstd.NewReader(r.Body) //nolint: errcheck
+			defer decoder.Close()
+
+			body = decoder
+		}
+
+		decoded, err := io.ReadAll(body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if attempts.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+
+		json.NewEncoder(w).Encode(compressionEcho{Encoding: encoding, Body: string(decoded)}) //nolint: errcheck
+	}))
+
+	return srv, rejected
+}
+
+func doCompressionEcho(t *testing.T, c *client.Client, request *client.Request) compressionEcho {
+	t.Helper()
+
+	var echo compressionEcho
+
+	response, err := c.Do(context.Background(), request)
+	require.NoError(t, err)
+	require.NoError(t, response.Unmarshal(&echo))
+
+	return echo
+}
+
+func TestClientRequestCompression(t *testing.T) {
+	srv, _ := newDecompressingHTTPServer(http.StatusOK, 0, false)
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRequestCompression(client.RequestCompression{Encoding: "gzip", Threshold: 100}),
+	)
+
+	large := strings.Repeat("measurement ", 100)
+
+	echo := doCompressionEcho(t, c, client.Post("series").WithPayload("text/plain", strings.NewReader("small")))
+	require.Equal(t, compressionEcho{Encoding: "", Body: "small"}, echo)
+
+	echo = doCompressionEcho(t, c, client.Post("series").WithPayload("text/plain", strings.NewReader(large)))
+	require.Equal(t, compressionEcho{Encoding: "gzip", Body: large}, echo)
+
+	echo = doCompressionEcho(t, c, client.Post("series").
+		WithCompression(client.RequestCompression{Encoding: "zstd"}).
+		WithPayload("text/plain", strings.NewReader("small")))
+	require.Equal(t, compressionEcho{Encoding: "zstd", Body: "small"}, echo)
+
+	echo = doCompressionEcho(t, c, client.Post("series").
+		WithCompression(client.RequestCompression{}).
+		WithPayload("text/plain", strings.NewReader(large)))
+	require.Equal(t, compressionEcho{Encoding: "", Body: large}, echo)
+
+	echo = doCompressionEcho(t, c, client.Get("series"))
+	require.Equal(t, compressionEcho{Encoding: "", Body: ""}, echo)
+}
+
+func TestClientRequestCompression_ResentOnRetry(t *testing.T) {
+	srv, _ := newDecompressingHTTPServer(http.StatusServiceUnavailable, 1, false)
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRequestCompression(client.RequestCompression{Encoding: "gzip"}),
+		client.WithRetry(client.RetryPolicy{
+			Backoff: &retry.ExponentialJitterBackoff{MaxAttempts: 2},
+		}),
+	)
+
+	payload := map[string][]int{"values": {1, 2, 3}}
+
+	echo := doCompressionEcho(t, c, client.Put("series").WithJSONPayload(payload))
+	require.Equal(t, "gzip", echo.Encoding)
+	require.JSONEq(t, `{"values":[1,2,3]}`, echo.Body)
+}
+
+func TestClientRequestCompression_UnsupportedMediaType(t *testing.T) {
+	srv, rejected := newDecompressingHTTPServer(http.StatusOK, 0, true)
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRequestCompression(client.RequestCompression{Encoding: "gzip"}),
+	)
+
+	for range 2 {
+		echo := doCompressionEcho(t, c, client.Post("series").WithJSONPayload("[1,2,3]"))
+		require.Equal(t, compressionEcho{Encoding: "", Body: "[1,2,3]"}, echo)
+	}
+
+	require.Equal(t, int32(1), rejected.Load())
+
+	_, err := c.Do(context.Background(), client.Post("series").
+		SetHeader(headers.ContentEncoding, "br").
+		WithPayload("text/plain", strings.NewReader("precompressed")))
+	require.ErrorIs(t, err, client.ErrUnsupportedMediaType)
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type key int
+
+const (
+	followRedirectsKey key = iota
+	uriTemplateKey
+	resourceNamerKey
+	codecsKey
+)
+
+// URITemplateFromContext returns the URI template of the Request being sent
+// with the context, such as "nodes/{id}". It is available to middlewares
+// through the context of the http.Request.
+func URITemplateFromContext(ctx context.Context) (string, bool) {
+	uriTemplate, ok := ctx.Value(uriTemplateKey).(string)
+	return uriTemplate, ok
+}
+
+// ResourceNamer names the resource targeted by a request in traces and
+// metrics. The name should have a low cardinality, i.e. not contain IDs.
+type ResourceNamer func(req *http.Request) string
+
+// DefaultResourceNamer names the resource by the method and the URI template
+// of the Request, such as "GET /nodes/{id}". Requests not sent by a Client
+// are named by their method and path.
+func DefaultResourceNamer(req *http.Request) string {
+	uriTemplate, ok := URITemplateFromContext(req.Context())
+	if !ok || uriTemplate == "" {
+		return req.Method + " " + req.URL.Path
+	}
+
+	if !strings.HasPrefix(uriTemplate, "/") && !strings.Contains(uriTemplate, "://") {
+		uriTemplate = "/" + uriTemplate
+	}
+
+	return req.Method + " " + uriTemplate
+}
+
+// ResourceName names the resource targeted by the request using the
+// ResourceNamer of the Client which sent it, or DefaultResourceNamer.
+func ResourceName(req *http.Request) string {
+	if namer, ok := req.Context().Value(resourceNamerKey).(ResourceNamer); ok && namer != nil {
+		return namer(req)
+	}
+
+	return DefaultResourceNamer(req)
+}
@@ -0,0 +1,71 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/mocktracer"
+
+	"github.com/SKF/go-rest-utility/client"
+)
+
+func TestDefaultResourceNamer_WithoutClient(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://example.com/nodes/42", nil)
+	require.NoError(t, err)
+
+	require.Equal(t, "GET /nodes/42", client.DefaultResourceNamer(req))
+}
+
+func TestClientDatadogResourceName(t *testing.T) {
+	tracer := mocktracer.Start()
+	defer tracer.Stop()
+
+	srv := newEchoHTTPServer()
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithDatadogTracing(),
+	)
+
+	for _, id := range []string{"1", "2"} {
+		response, err := c.Do(context.Background(), client.Get("nodes/{id}").Assign("id", id))
+		require.NoError(t, err)
+		require.NoError(t, response.Close())
+	}
+
+	spans := tracer.FinishedSpans()
+	require.Len(t, spans, 2)
+
+	for _, span := range spans {
+		require.Equal(t, "GET /nodes/{id}", span.Tag(ext.ResourceName))
+	}
+}
+
+func TestClientCustomResourceNamer(t *testing.T) {
+	tracer := mocktracer.Start()
+	defer tracer.Stop()
+
+	srv := newEchoHTTPServer()
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithDatadogTracing(),
+		client.WithResourceNamer(func(req *http.Request) string {
+			uriTemplate, _ := client.URITemplateFromContext(req.Context())
+			return "hierarchy " + uriTemplate
+		}),
+	)
+
+	response, err := c.Do(context.Background(), client.Get("nodes/{id}").Assign("id", "1"))
+	require.NoError(t, err)
+	require.NoError(t, response.Close())
+
+	spans := tracer.FinishedSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, "hierarchy nodes/{id}", spans[0].Tag(ext.ResourceName))
+}
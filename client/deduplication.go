@@ -0,0 +1,192 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-http-utils/headers"
+	"golang.org/x/sync/singleflight"
+)
+
+// MaxDeduplicatedBodySize is the largest response body shared between
+// deduplicated requests. A larger response is streamed to the request which
+// sent it, while the others are sent on their own.
+const MaxDeduplicatedBodySize = 4 << 20
+
+// deduplicationIgnoredHeaders are headers which differ between otherwise
+// identical requests without affecting the response.
+var deduplicationIgnoredHeaders = []string{
+	"Traceparent",
+	"Tracestate",
+	"Baggage",
+	"X-Cloud-Trace-Context",
+	"X-Correlation-Id",
+	"X-Request-Id",
+}
+
+// DeduplicationMiddleware lets identical concurrent GET and HEAD requests
+// share a single round trip. Requests are identical if they have the same
+// URL and headers, ignoring trace headers.
+//
+// The shared response body is buffered in memory, up to
+// MaxDeduplicatedBodySize, and every request gets an independent copy of the
+// response. Requests accepting streams, `text/event-stream` or
+// `application/x-ndjson`, and range requests are never deduplicated, as they
+// are not meant to be buffered.
+func DeduplicationMiddleware() Middleware {
+	group := new(singleflight.Group)
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if !isDeduplicable(req) {
+				return next.RoundTrip(req)
+			}
+
+			results := group.DoChan(deduplicationKey(req), func() (interface{}, error) {
+				return roundTripBuffered(next, req, MaxDeduplicatedBodySize)
+			})
+
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case result := <-results:
+				// The shared request was cancelled by another caller, so
+				// this request has to be sent on its own.
+				if isContextError(result.Err) && req.Context().Err() == nil {
+					return next.RoundTrip(req)
+				}
+
+				if result.Err != nil {
+					return nil, result.Err
+				}
+
+				buffered := result.Val.(*bufferedResponse)
+				if buffered.stream != nil {
+					return buffered.streamed(next, req)
+				}
+
+				return buffered.copy(req), nil
+			}
+		})
+	}
+}
+
+func isDeduplicable(req *http.Request) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+
+	if req.Header.Get(headers.Range) != "" || acceptsMediaType(req.Header, eventStreamMediaType, ndjsonMediaType) {
+		return false
+	}
+
+	return req.Body == nil || req.Body == http.NoBody
+}
+
+func acceptsMediaType(header http.Header, mediaTypes ...string) bool {
+	for _, value := range header.Values(headers.Accept) {
+		for _, accepted := range strings.Split(value, ",") {
+			accepted, _, _ = strings.Cut(accepted, ";")
+			accepted = strings.ToLower(strings.TrimSpace(accepted))
+			if slices.Contains(mediaTypes, accepted) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func deduplicationKey(req *http.Request) string {
+	var key strings.Builder
+
+	key.WriteString(req.Method)
+	key.WriteString(" ")
+	key.WriteString(req.URL.String())
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		if !slices.Contains(deduplicationIgnoredHeaders, name) {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			key.WriteString("\n")
+			key.WriteString(name)
+			key.WriteString(": ")
+			key.WriteString(value)
+		}
+	}
+
+	return key.String()
+}
+
+type bufferedResponse struct {
+	response *http.Response
+	body     []byte
+
+	// stream is the unread body of a response larger than the limit, which
+	// only the request which sent it, owner, can read.
+	stream io.ReadCloser
+	owner  *http.Request
+}
+
+func roundTripBuffered(next http.RoundTripper, req *http.Request, limit int64) (*bufferedResponse, error) {
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		resp.Body.Close() //nolint: errcheck
+		return nil, err
+	}
+
+	if int64(len(body)) > limit {
+		return &bufferedResponse{response: resp, body: body, stream: resp.Body, owner: req}, nil
+	}
+
+	resp.Body.Close() //nolint: errcheck
+
+	return &bufferedResponse{response: resp, body: body}, nil
+}
+
+// streamed returns the response with the buffered and the unread body to the
+// request which sent it, the other requests are sent on their own.
+func (b *bufferedResponse) streamed(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	if req != b.owner {
+		return next.RoundTrip(req)
+	}
+
+	resp := *b.response
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(b.body), b.stream), b.stream}
+
+	return &resp, nil
+}
+
+func (b *bufferedResponse) copy(req *http.Request) *http.Response {
+	resp := *b.response
+	resp.Header = b.response.Header.Clone()
+	resp.Trailer = b.response.Trailer.Clone()
+	resp.Body = io.NopCloser(bytes.NewReader(b.body))
+	resp.Request = req
+
+	return &resp
+}
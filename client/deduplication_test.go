@@ -0,0 +1,229 @@
+package client_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client"
+)
+
+func newBlockingHTTPServer(release <-chan struct{}) (*httptest.Server, *atomic.Int32) {
+	requests := new(atomic.Int32)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+
+		w.Write([]byte(r.URL.Path)) //nolint: errcheck
+	}))
+
+	return srv, requests
+}
+
+func TestClientDeduplication(t *testing.T) {
+	release := make(chan struct{})
+
+	srv, requests := newBlockingHTTPServer(release)
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithDeduplication(),
+	)
+
+	const callers = 10
+
+	var wg sync.WaitGroup
+
+	bodies := make([]string, callers)
+
+	for i := range callers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			response, err := c.Do(context.Background(), client.Get("nodes/{id}").Assign("id", "42"))
+			require.NoError(t, err)
+
+			defer response.Close()
+
+			body, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+
+			bodies[i] = string(body)
+		}()
+	}
+
+	require.Eventually(t, func() bool { return requests.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, int32(1), requests.Load())
+
+	for _, body := range bodies {
+		require.Equal(t, "/nodes/42", body)
+	}
+}
+
+func TestClientDeduplication_OnlyIdenticalGetRequests(t *testing.T) {
+	release := make(chan struct{})
+
+	srv, requests := newBlockingHTTPServer(release)
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithDeduplication(),
+	)
+
+	requestsToSend := []*client.Request{
+		client.Get("nodes/1"),
+		client.Get("nodes/2"),
+		client.Get("nodes/1").SetHeader("Accept-Language", "sv"),
+		client.Post("nodes/1"),
+	}
+
+	var wg sync.WaitGroup
+
+	for _, request := range requestsToSend {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			response, err := c.Do(context.Background(), request)
+			require.NoError(t, err)
+			require.NoError(t, response.Close())
+		}()
+	}
+
+	require.Eventually(t, func() bool { return requests.Load() == int32(len(requestsToSend)) }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+}
+
+func TestClientDeduplication_Streams(t *testing.T) {
+	sse := newEndlessHTTPServer(t, "text/event-stream", "data: first\n\n", ": keep-alive\n\n")
+	ndjson := newEndlessHTTPServer(t, "application/x-ndjson", "{\"x\": 1}\n", "{\"x\": 2}\n")
+
+	requireReturns(t, func() {
+		c := client.NewClient(client.WithBaseURL(sse.URL), client.WithDeduplication())
+
+		for event, err := range c.Subscribe(context.Background(), client.Get("events")) {
+			require.NoError(t, err)
+			require.Equal(t, "first", event.Data)
+
+			break
+		}
+	})
+
+	requireReturns(t, func() {
+		c := client.NewClient(client.WithBaseURL(ndjson.URL), client.WithDeduplication())
+
+		response, err := c.Do(context.Background(), client.Get("points").SetHeader(headers.Accept, "application/x-ndjson"))
+		require.NoError(t, err)
+
+		for p, err := range client.StreamNDJSON[point](response) {
+			require.NoError(t, err)
+			require.Equal(t, point{1}, p)
+
+			break
+		}
+	})
+}
+
+func TestClientDeduplication_LargeResponse(t *testing.T) {
+	release := make(chan struct{})
+	requests := new(atomic.Int32)
+	content := strings.Repeat("x", client.MaxDeduplicatedBodySize+1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		<-release
+
+		w.Write([]byte(content)) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithDeduplication(),
+	)
+
+	const callers = 3
+
+	var wg sync.WaitGroup
+
+	bodies := make([]string, callers)
+
+	for i := range callers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			response, err := c.Do(context.Background(), client.Get("firmware"))
+			require.NoError(t, err)
+
+			defer response.Close()
+
+			body, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+
+			bodies[i] = string(body)
+		}()
+	}
+
+	require.Eventually(t, func() bool { return requests.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, int32(callers), requests.Load())
+
+	for _, body := range bodies {
+		require.Equal(t, content, body)
+	}
+}
+
+func TestClientDeduplication_RangeRequests(t *testing.T) {
+	release := make(chan struct{})
+
+	srv, requests := newBlockingHTTPServer(release)
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithDeduplication(),
+	)
+
+	var wg sync.WaitGroup
+
+	for range 2 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			response, err := c.Do(context.Background(), client.Get("firmware").SetHeader(headers.Range, "bytes=0-"))
+			require.NoError(t, err)
+			require.NoError(t, response.Close())
+		}()
+	}
+
+	require.Eventually(t, func() bool { return requests.Load() == 2 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+}
@@ -0,0 +1,431 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-http-utils/headers"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/SKF/go-rest-utility/problems"
+)
+
+const (
+	// DefaultDownloadAttempts is the number of times a range of a download is
+	// requested, including the resumptions after transient failures.
+	DefaultDownloadAttempts = 5
+
+	// DefaultDownloadConcurrency is the number of chunks downloaded at the
+	// same time when downloading in chunks.
+	DefaultDownloadConcurrency = 4
+)
+
+var (
+	ErrResourceChanged     = errors.New("resource changed during download")
+	ErrInvalidContentRange = errors.New("invalid content range")
+)
+
+// DownloadOption configures a single Client.Download.
+type DownloadOption func(*downloadConfig)
+
+type downloadConfig struct {
+	chunkSize   int64
+	concurrency int
+	attempts    int
+}
+
+// WithChunks downloads the resource in ranges of size bytes, at most
+// concurrency of them at the same time, or DefaultDownloadConcurrency if not
+// positive. Servers not supporting range requests are downloaded with a
+// single request.
+func WithChunks(size int64, concurrency int) DownloadOption {
+	return func(config *downloadConfig) {
+		config.chunkSize = size
+		config.concurrency = concurrency
+	}
+}
+
+// WithDownloadAttempts sets the number of times each range is requested,
+// including the resumptions after transient failures.
+func WithDownloadAttempts(attempts int) DownloadOption {
+	return func(config *downloadConfig) {
+		config.attempts = attempts
+	}
+}
+
+// byteRange is an inclusive range of bytes, where a negative end is the end
+// of the resource.
+type byteRange struct {
+	start, end int64
+}
+
+// download is a single download of a resource into a writer.
+type download struct {
+	downloadConfig
+
+	client *Client
+	req    *Request
+	w      io.WriterAt
+
+	// validator is the strong entity tag or the modification date of the
+	// resource, which resumed ranges are requested with `If-Range` on.
+	validator string
+	etag      string
+	progress  *progressCounter
+}
+
+// Download requests the resource of the GET request and writes its body
+// into w, which can be resumed with `Range` requests when the transfer fails
+// halfway through.
+//
+// Resuming requires the server to respond with a strong `ETag` or a
+// `Last-Modified` date, which is sent as `If-Range` such that a resource
+// which has changed in between fails with ErrResourceChanged, rather than
+// being mixed with the old one. Without either the resource is downloaded
+// with a single request, even WithChunks. A Request with a ProgressFunc reports the
+// bytes written into w.
+//
+//	file, _ := os.Create("firmware.bin")
+//	meta, err := c.Download(ctx, client.Get("firmware/{version}").Assign("version", version), file,
+//		client.WithChunks(8<<20, 4))
+func (c *Client) Download(ctx context.Context, r *Request, w io.WriterAt, opts ...DownloadOption) (*ResponseMeta, error) {
+	d := &download{
+		downloadConfig: downloadConfig{
+			concurrency: DefaultDownloadConcurrency,
+			attempts:    DefaultDownloadAttempts,
+		},
+		client: c,
+		req:    r,
+		w:      w,
+	}
+
+	for _, opt := range opts {
+		opt(&d.downloadConfig)
+	}
+
+	first := byteRange{start: 0, end: -1}
+	if d.chunkSize > 0 {
+		first.end = d.chunkSize - 1
+	}
+
+	response, err := d.request(ctx, first)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := newResponseMeta(response)
+
+	total, ranged, err := d.start(response)
+	if err != nil {
+		closeStream(response)
+		return meta, err
+	}
+
+	if ranged && d.validator == "" {
+		// Without a validator the ranges could be of different versions of
+		// the resource, so it is downloaded with a single request instead.
+		closeStream(response)
+
+		first = byteRange{start: 0, end: -1}
+
+		if response, err = d.request(ctx, first); err != nil {
+			return meta, err
+		}
+
+		meta = newResponseMeta(response)
+
+		total, ranged, err = d.start(response)
+		if err == nil && ranged {
+			err = fmt.Errorf("%w: range of a request without range", ErrInvalidContentRange)
+		}
+
+		if err != nil {
+			closeStream(response)
+			return meta, err
+		}
+	}
+
+	switch {
+	case !ranged:
+		first.end = total - 1
+	case total >= 0:
+		first.end = min(first.end, total-1)
+	}
+
+	if err := d.fetch(ctx, first, response); err != nil {
+		return meta, err
+	}
+
+	if !ranged {
+		return meta, nil
+	}
+
+	if total < 0 {
+		// The size is unknown, so the rest is downloaded as a single range.
+		err := d.fetch(ctx, byteRange{start: first.end + 1, end: -1}, nil)
+		if errors.Is(err, ErrRequestedRangeNotSatisfiable) {
+			return meta, nil
+		}
+
+		return meta, err
+	}
+
+	return meta, d.fetchChunks(ctx, first.end+1, total)
+}
+
+// start inspects the first response, returning the total size of the
+// resource, or -1 if unknown, and whether the server responded with a range.
+func (d *download) start(response *Response) (int64, bool, error) {
+	d.etag = response.ETag()
+
+	if !strings.HasPrefix(d.etag, "W/") {
+		d.validator = d.etag
+	}
+
+	if d.validator == "" {
+		d.validator = response.Header.Get(headers.LastModified)
+	}
+
+	total := response.ContentLength
+	ranged := response.StatusCode == http.StatusPartialContent
+
+	if ranged {
+		var err error
+		if _, total, err = parseContentRange(response.Header.Get(headers.ContentRange)); err != nil {
+			return 0, false, err
+		}
+	}
+
+	if d.req.progress != nil {
+		d.progress = &progressCounter{fn: d.req.progress, total: total}
+	}
+
+	return total, ranged, nil
+}
+
+// fetchChunks downloads the resource from offset until total in chunks.
+func (d *download) fetchChunks(ctx context.Context, offset, total int64) error {
+	group, ctx := errgroup.WithContext(ctx)
+
+	if d.concurrency > 0 {
+		group.SetLimit(d.concurrency)
+	}
+
+	for start := offset; start < total; start += d.chunkSize {
+		chunk := byteRange{start: start, end: min(start+d.chunkSize, total) - 1}
+
+		group.Go(func() error {
+			return d.fetch(ctx, chunk, nil)
+		})
+	}
+
+	return group.Wait()
+}
+
+// fetch writes the range into w, resuming from where the previous attempt
+// stopped on transient failures. The response, if not nil, is the response
+// of the first attempt.
+func (d *download) fetch(ctx context.Context, rng byteRange, response *Response) error {
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		if response == nil {
+			response, err = d.request(ctx, rng)
+			if err == nil {
+				err = d.check(response, rng.start)
+			}
+		}
+
+		if err == nil {
+			var n int64
+
+			n, err = d.copy(response, rng.start)
+			rng.start += n
+
+			if err == nil && rng.end >= 0 && rng.start <= rng.end {
+				err = io.ErrUnexpectedEOF
+			}
+		}
+
+		// The rest of the body is not drained, as it may be all of what was
+		// left to download.
+		if response != nil {
+			closeStream(response)
+			response = nil
+		}
+
+		if err == nil {
+			return nil
+		}
+
+		if ctx.Err() != nil || attempt >= d.attempts || !d.resumable(err) {
+			return err
+		}
+
+		if err := d.backoff(ctx, attempt); err != nil {
+			return err
+		}
+	}
+}
+
+func (d *download) copy(response *Response, offset int64) (int64, error) {
+	var body io.ReadCloser = response.Body
+	if d.progress != nil {
+		body = progressReader{ReadCloser: body, counter: d.progress}
+	}
+
+	n, err := io.Copy(downloadWriter{io.NewOffsetWriter(d.w, offset)}, body)
+	if err != nil {
+		return n, fmt.Errorf("failed to download range: %w", err)
+	}
+
+	return n, nil
+}
+
+// downloadWriter marks the errors of writing the download, which unlike
+// transfer errors can not be resumed.
+type downloadWriter struct {
+	w io.Writer
+}
+
+type writeError struct {
+	err error
+}
+
+func (e writeError) Error() string { return e.err.Error() }
+func (e writeError) Unwrap() error { return e.err }
+
+func (dw downloadWriter) Write(p []byte) (int, error) {
+	n, err := dw.w.Write(p)
+	if err != nil {
+		return n, writeError{err}
+	}
+
+	return n, nil
+}
+
+// request requests the range of the resource, the whole resource if the
+// range starts at 0 and is open-ended.
+func (d *download) request(ctx context.Context, rng byteRange) (*Response, error) {
+	request := d.req.Clone()
+	request.progress = nil
+
+	// Ranges apply to the encoded body, so it must not be encoded.
+	request.SetHeader(headers.AcceptEncoding, "identity")
+
+	if rng.start > 0 || rng.end >= 0 {
+		spec := fmt.Sprintf("bytes=%d-", rng.start)
+		if rng.end >= 0 {
+			spec += strconv.FormatInt(rng.end, 10)
+		}
+
+		request.SetHeader(headers.Range, spec)
+
+		if d.validator != "" {
+			request.SetHeader(headers.IfRange, d.validator)
+		}
+	}
+
+	return d.client.Do(ctx, request)
+}
+
+// check verifies that the response of a resumed or chunked range is the
+// requested range of the same resource.
+func (d *download) check(response *Response, offset int64) error {
+	if response.StatusCode != http.StatusPartialContent {
+		return ErrResourceChanged
+	}
+
+	if etag := response.ETag(); d.etag != "" && etag != "" && etag != d.etag {
+		return ErrResourceChanged
+	}
+
+	start, _, err := parseContentRange(response.Header.Get(headers.ContentRange))
+	if err != nil {
+		return err
+	}
+
+	if start != offset {
+		return fmt.Errorf("%w: expected range from %d, got %d", ErrInvalidContentRange, offset, start)
+	}
+
+	return nil
+}
+
+// resumable reports whether the failed transfer may be resumed, which
+// requires a validator to not mix an old and a new version of the resource.
+func (d *download) resumable(err error) bool {
+	if d.validator == "" || errors.Is(err, ErrResourceChanged) || errors.Is(err, ErrInvalidContentRange) {
+		return false
+	}
+
+	var writeErr writeError
+	if errors.As(err, &writeErr) {
+		return false
+	}
+
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.IsRetryable()
+	}
+
+	var problem problems.Problem
+
+	return !errors.As(err, &problem)
+}
+
+// backoff waits before resuming, as configured by the RetryPolicy of the
+// Client if any.
+func (d *download) backoff(ctx context.Context, attempt int) error {
+	policy := d.client.retryPolicy
+	if policy == nil || policy.Backoff == nil {
+		return nil
+	}
+
+	delay, err := policy.Backoff.BackoffByAttempt(attempt)
+	if err != nil {
+		return fmt.Errorf("failed generating resume backoff: %w", err)
+	}
+
+	return sleepContext(ctx, delay)
+}
+
+// parseContentRange parses the start and the complete length of a
+// `Content-Range` header, the length is -1 if unknown.
+func parseContentRange(value string) (int64, int64, error) {
+	spec, found := strings.CutPrefix(value, "bytes ")
+	if !found {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidContentRange, value)
+	}
+
+	rng, length, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidContentRange, value)
+	}
+
+	first, _, found := strings.Cut(rng, "-")
+	if !found {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidContentRange, value)
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidContentRange, value)
+	}
+
+	if length == "*" {
+		return start, -1, nil
+	}
+
+	total, err := strconv.ParseInt(length, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidContentRange, value)
+	}
+
+	return start, total, nil
+}
@@ -0,0 +1,321 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client"
+)
+
+// downloadServer serves a file with range requests, cutting the first
+// failures responses short after a few bytes.
+type downloadServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	content  []byte
+	etag     string
+	failures int
+	ranges   []string
+	ifRanges []string
+}
+
+func newDownloadServer(content []byte, etag string, failures int) *downloadServer {
+	ds := &downloadServer{content: content, etag: etag, failures: failures}
+
+	ds.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ds.mu.Lock()
+		ds.ranges = append(ds.ranges, r.Header.Get(headers.Range))
+		ds.ifRanges = append(ds.ifRanges, r.Header.Get(headers.IfRange))
+		content, etag, cut := ds.content, ds.etag, ds.failures > 0
+		ds.failures--
+		ds.mu.Unlock()
+
+		if etag != "" {
+			w.Header().Set(headers.ETag, etag)
+		}
+
+		if cut {
+			w = &cuttingResponseWriter{ResponseWriter: w, remaining: 1000}
+		}
+
+		http.ServeContent(w, r, "firmware.bin", time.Time{}, bytes.NewReader(content))
+	}))
+
+	return ds
+}
+
+func (ds *downloadServer) replace(content []byte, etag string) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	ds.content, ds.etag = content, etag
+}
+
+func (ds *downloadServer) requests() ([]string, []string) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	return ds.ranges, ds.ifRanges
+}
+
+// cuttingResponseWriter aborts the response after remaining bytes.
+type cuttingResponseWriter struct {
+	http.ResponseWriter
+	remaining int
+}
+
+func (w *cuttingResponseWriter) Write(p []byte) (int, error) {
+	if len(p) > w.remaining {
+		w.ResponseWriter.Write(p[:w.remaining]) //nolint: errcheck
+		w.ResponseWriter.(http.Flusher).Flush()
+
+		panic(http.ErrAbortHandler)
+	}
+
+	w.remaining -= len(p)
+
+	return w.ResponseWriter.Write(p)
+}
+
+func randomContent(size int) []byte {
+	content := make([]byte, size)
+
+	rng := rand.New(rand.NewPCG(1, 2)) //nolint: gosec
+	for i := range content {
+		content[i] = byte(rng.UintN(256))
+	}
+
+	return content
+}
+
+func downloadFile(t *testing.T) (*os.File, func() []byte) {
+	t.Helper()
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "download"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { file.Close() })
+
+	return file, func() []byte {
+		content, err := os.ReadFile(file.Name())
+		require.NoError(t, err)
+
+		return content
+	}
+}
+
+func TestClientDownload(t *testing.T) {
+	content := randomContent(100_000)
+
+	srv := newDownloadServer(content, `"v1"`, 0)
+	defer srv.Close()
+
+	var (
+		mu       sync.Mutex
+		progress []int64
+	)
+
+	file, downloaded := downloadFile(t)
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	meta, err := c.Download(context.Background(), client.Get("firmware").WithProgress(func(transferred, total int64) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		require.Equal(t, int64(len(content)), total)
+
+		progress = append(progress, transferred)
+	}), file)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, meta.StatusCode)
+	require.Equal(t, `"v1"`, meta.ETag)
+	require.Equal(t, content, downloaded())
+
+	require.NotEmpty(t, progress)
+	require.IsIncreasing(t, progress)
+	require.Equal(t, int64(len(content)), progress[len(progress)-1])
+
+	ranges, _ := srv.requests()
+	require.Equal(t, []string{""}, ranges)
+}
+
+func TestClientDownload_Resume(t *testing.T) {
+	content := randomContent(100_000)
+
+	srv := newDownloadServer(content, `"v1"`, 2)
+	defer srv.Close()
+
+	file, downloaded := downloadFile(t)
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	_, err := c.Download(context.Background(), client.Get("firmware"), file)
+	require.NoError(t, err)
+	require.Equal(t, content, downloaded())
+
+	ranges, ifRanges := srv.requests()
+	require.Len(t, ranges, 3)
+	require.Equal(t, "", ranges[0])
+	require.Regexp(t, `^bytes=\d+-99999$`, ranges[1])
+	require.Regexp(t, `^bytes=\d+-99999$`, ranges[2])
+	require.Equal(t, []string{"", `"v1"`, `"v1"`}, ifRanges)
+}
+
+func TestClientDownload_Chunks(t *testing.T) {
+	content := randomContent(100_000)
+
+	srv := newDownloadServer(content, `"v1"`, 3)
+	defer srv.Close()
+
+	file, downloaded := downloadFile(t)
+
+	var (
+		mu          sync.Mutex
+		transferred int64
+	)
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	meta, err := c.Download(context.Background(), client.Get("firmware").WithProgress(func(n, _ int64) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		transferred = n
+	}), file, client.WithChunks(30_000, 2))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusPartialContent, meta.StatusCode)
+	require.Equal(t, content, downloaded())
+	require.Equal(t, int64(len(content)), transferred)
+
+	ranges, ifRanges := srv.requests()
+	require.Len(t, ranges, 7)
+	require.Equal(t, "bytes=0-29999", ranges[0])
+	require.Equal(t, "", ifRanges[0])
+	require.Contains(t, ranges, "bytes=90000-99999")
+}
+
+func TestClientDownload_ResourceChanged(t *testing.T) {
+	content := randomContent(100_000)
+
+	srv := newDownloadServer(content, `"v1"`, 1)
+	defer srv.Close()
+
+	file, _ := downloadFile(t)
+
+	c := client.NewClient(client.WithBaseURL(srv.URL), client.WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		return client.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(headers.Range) != "" {
+				srv.replace(randomContent(50_000), `"v2"`)
+			}
+
+			return next.RoundTrip(req)
+		})
+	}))
+
+	_, err := c.Download(context.Background(), client.Get("firmware"), file)
+	require.ErrorIs(t, err, client.ErrResourceChanged)
+}
+
+func TestClientDownload_WithoutValidator(t *testing.T) {
+	srv := newDownloadServer(randomContent(100_000), "", 1)
+	defer srv.Close()
+
+	file, _ := downloadFile(t)
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	_, err := c.Download(context.Background(), client.Get("firmware"), file)
+	require.Error(t, err)
+
+	ranges, _ := srv.requests()
+	require.Len(t, ranges, 1)
+}
+
+func TestClientDownload_ChunksWithoutValidator(t *testing.T) {
+	content := randomContent(100_000)
+
+	srv := newDownloadServer(content, "", 0)
+	defer srv.Close()
+
+	file, downloaded := downloadFile(t)
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	meta, err := c.Download(context.Background(), client.Get("firmware"), file, client.WithChunks(30_000, 2))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, meta.StatusCode)
+	require.Equal(t, content, downloaded())
+
+	ranges, ifRanges := srv.requests()
+	require.Equal(t, []string{"bytes=0-29999", ""}, ranges)
+	require.Equal(t, []string{"", ""}, ifRanges)
+}
+
+// failingWriterAt fails every write, as a full disk would.
+type failingWriterAt struct{}
+
+func (failingWriterAt) WriteAt([]byte, int64) (int, error) {
+	return 0, errors.New("no space left on device")
+}
+
+func TestClientDownload_WriteErrorNotDrained(t *testing.T) {
+	srv := newEndlessHTTPServer(t, "application/octet-stream", "firmware", "firmware")
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	requireReturns(t, func() {
+		_, err := c.Download(context.Background(), client.Get("firmware"), failingWriterAt{})
+		require.ErrorContains(t, err, "no space left on device")
+	})
+}
+
+func TestClientDownload_AttemptsExhausted(t *testing.T) {
+	srv := newDownloadServer(randomContent(100_000), `"v1"`, 10)
+	defer srv.Close()
+
+	file, _ := downloadFile(t)
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	_, err := c.Download(context.Background(), client.Get("firmware"), file, client.WithDownloadAttempts(3))
+	require.Error(t, err)
+
+	ranges, _ := srv.requests()
+	require.Len(t, ranges, 3)
+}
+
+func TestRequestProgress_Upload(t *testing.T) {
+	srv := newEchoHTTPServer()
+	defer srv.Close()
+
+	payload := bytes.Repeat([]byte("measurement "), 10_000)
+
+	var progress [][2]int64
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	response, err := c.Do(context.Background(), client.Post("series").
+		WithPayload("text/plain", bytes.NewReader(payload)).
+		WithProgress(func(transferred, total int64) {
+			progress = append(progress, [2]int64{transferred, total})
+		}))
+	require.NoError(t, err)
+	require.NoError(t, response.Close())
+
+	require.NotEmpty(t, progress)
+	require.Equal(t, [2]int64{int64(len(payload)), int64(len(payload))}, progress[len(progress)-1])
+}
@@ -0,0 +1,191 @@
+package client
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// DefaultMaxDecompressedSize is the default limit of the size of a response
+// body after decompression, protecting against decompression bombs.
+const DefaultMaxDecompressedSize int64 = 1 << 30
+
+// DecompressedSizeError is returned when reading a response body whose size
+// after decompression exceeds the limit.
+type DecompressedSizeError struct {
+	Limit int64
+}
+
+func (e *DecompressedSizeError) Error() string {
+	return fmt.Sprintf("decompressed body exceeds the limit of %d bytes", e.Limit)
+}
+
+// ContentDecoder decodes a body with a content coding. Closing the returned
+// reader must release the resources of the decoder, but not close r.
+type ContentDecoder func(r io.Reader) (io.ReadCloser, error)
+
+var contentDecoders = struct {
+	sync.RWMutex
+	decoders map[string]ContentDecoder
+	names    []string
+}{
+	decoders: make(map[string]ContentDecoder),
+}
+
+func init() {
+	RegisterContentEncoding("gzip", decodeGzip)
+	RegisterContentEncoding("deflate", decodeDeflate)
+	RegisterContentEncoding("br", decodeBrotli)
+	RegisterContentEncoding("zstd", decodeZstd)
+}
+
+// RegisterContentEncoding registers the decoder of a content coding, used by
+// DecompressResponse and advertised by AcceptEncoding.
+func RegisterContentEncoding(name string, decoder ContentDecoder) {
+	contentDecoders.Lock()
+	defer contentDecoders.Unlock()
+
+	name = strings.ToLower(name)
+
+	if _, exists := contentDecoders.decoders[name]; !exists {
+		contentDecoders.names = append(contentDecoders.names, name)
+	}
+
+	contentDecoders.decoders[name] = decoder
+}
+
+// AcceptEncoding returns the value of the Accept-Encoding header listing
+// every registered content coding, such as "gzip, deflate, br, zstd".
+func AcceptEncoding() string {
+	contentDecoders.RLock()
+	defer contentDecoders.RUnlock()
+
+	return strings.Join(contentDecoders.names, ", ")
+}
+
+func lookupContentDecoder(name string) (ContentDecoder, bool) {
+	contentDecoders.RLock()
+	defer contentDecoders.RUnlock()
+
+	decoder, exists := contentDecoders.decoders[name]
+
+	return decoder, exists
+}
+
+// contentCodings returns the content codings of the header in the order they
+// were applied, leaving out identity.
+func contentCodings(values []string) []string {
+	var codings []string
+
+	for _, value := range values {
+		for _, coding := range strings.Split(value, ",") {
+			coding = strings.ToLower(strings.TrimSpace(coding))
+			if coding != "" && coding != "identity" {
+				codings = append(codings, coding)
+			}
+		}
+	}
+
+	return codings
+}
+
+// decodedBody reads a body through a stack of decoders, which are closed
+// together with the body.
+type decodedBody struct {
+	io.Reader
+	decoders []io.Closer
+	body     io.Closer
+}
+
+func (b *decodedBody) Close() error {
+	var err error
+
+	for i := len(b.decoders) - 1; i >= 0; i-- {
+		if closeErr := b.decoders[i].Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	if closeErr := b.body.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// limitedReader fails with a DecompressedSizeError instead of returning more
+// than limit bytes.
+type limitedReader struct {
+	reader    io.Reader
+	limit     int64
+	remaining int64
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, &DecompressedSizeError{Limit: r.limit}
+	}
+
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+
+	n, err := r.reader.Read(p)
+
+	if r.remaining -= int64(n); r.remaining < 0 {
+		return n + int(r.remaining), &DecompressedSizeError{Limit: r.limit}
+	}
+
+	return n, err
+}
+
+func decodeGzip(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// decodeDeflate decodes the zlib format as specified for deflate, but also
+// the raw deflate format sent by some servers.
+func decodeDeflate(r io.Reader) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(r)
+
+	header, err := buffered.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(buffered)
+	}
+
+	return flate.NewReader(buffered), nil
+}
+
+func decodeBrotli(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(brotli.NewReader(r)), nil
+}
+
+type zstdReader struct {
+	*zstd.Decoder
+}
+
+func (r zstdReader) Close() error {
+	r.Decoder.Close()
+	return nil
+}
+
+func decodeZstd(r io.Reader) (io.ReadCloser, error) {
+	decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+
+	return zstdReader{decoder}, nil
+}
@@ -1,9 +1,22 @@
 package client
 
 import (
+	"context"
+	"encoding/binary"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-http-utils/headers"
+	opencensus "go.opencensus.io/trace"
+	"go.opentelemetry.io/otel/trace"
+	datadog "gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
 )
 
 var (
@@ -50,12 +63,44 @@ var (
 	ErrNetworkAuthenticationRequired = newHTTPError(http.StatusNetworkAuthenticationRequired)
 )
 
+// DefaultMaxErrorBodySize is the number of bytes of an error response body
+// kept in the HTTPError unless configured with WithMaxErrorBodySize.
+const DefaultMaxErrorBodySize int64 = 64 << 10
+
+// correlationHeaders are response headers which may identify the request in
+// the logs of the server, in order of preference.
+var correlationHeaders = []string{
+	"X-Correlation-Id",
+	"X-Request-Id",
+	"X-Amzn-Requestid",
+	"X-Amzn-Trace-Id",
+}
+
 type HTTPError struct {
 	StatusCode int
 	Status     string
 
+	// Method is the method of the failed request.
+	Method   string
 	Instance string
-	Body     string
+
+	// Meta holds the headers of the error response.
+	Meta *ResponseMeta
+
+	// Body is the body of the error response if it is human readable,
+	// capped to the configured maximum size.
+	Body string
+	// BodyTruncated is set if the body exceeded the maximum size.
+	BodyTruncated bool
+
+	// RetryAfter is the duration the server asked to wait before retrying.
+	RetryAfter time.Duration
+
+	// CorrelationID identifies the request in the logs of the server or in
+	// the distributed trace.
+	CorrelationID string
+
+	rawBody string
 }
 
 func newHTTPError(statusCode int) HTTPError {
@@ -70,26 +115,99 @@ func (e HTTPError) withInstance(instance string) HTTPError {
 	return e
 }
 
-func (e HTTPError) withBody(reader io.ReadCloser) HTTPError {
+func (e HTTPError) withResponse(resp *http.Response) HTTPError {
+	e.Meta = newResponseMeta(&Response{*resp})
+
+	if retryAfter, ok := parseRetryAfter(resp.Header, time.Now()); ok {
+		e.RetryAfter = retryAfter
+	}
+
+	for _, header := range correlationHeaders {
+		if e.CorrelationID = resp.Header.Get(header); e.CorrelationID != "" {
+			break
+		}
+	}
+
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e = e.withInstance(resp.Request.URL.String())
+
+		if e.CorrelationID == "" {
+			e.CorrelationID = traceID(resp.Request.Context())
+		}
+	}
+
+	return e
+}
+
+func (e HTTPError) withBody(reader io.ReadCloser, maxSize int64) HTTPError {
 	defer reader.Close()
 
-	if body, err := io.ReadAll(reader); err == nil {
-		// Assumed to be human readable. Content-type should probably be checked in the future. Or be changed to bytes.
-		e.Body = string(body)
+	var limited io.Reader = reader
+	if maxSize > 0 {
+		limited = io.LimitReader(reader, maxSize+1)
+	}
+
+	body, err := io.ReadAll(limited)
+	if err != nil {
+		return e
+	}
+
+	if maxSize > 0 && int64(len(body)) > maxSize {
+		body = body[:maxSize]
+		e.BodyTruncated = true
+	}
+
+	e.rawBody = string(body)
+
+	contentType := ""
+	if e.Meta != nil {
+		contentType = e.Meta.Header.Get(headers.ContentType)
+	}
+
+	if isHumanReadable(contentType, body) {
+		e.Body = e.rawBody
 	}
 
 	return e
 }
 
+// RawBody returns the, possibly binary, body of the error response capped to
+// the configured maximum size.
+func (e HTTPError) RawBody() []byte {
+	return []byte(e.rawBody)
+}
+
+// IsRetryable reports whether the request may succeed if sent again, which
+// requires a transient status code and an idempotent request method.
+func (e HTTPError) IsRetryable() bool {
+	if e.Method != "" && !isIdempotent(e.Method) {
+		return false
+	}
+
+	return slices.Contains(DefaultRetryStatusCodes, e.StatusCode)
+}
+
 func (e HTTPError) Error() string {
+	methodText := ""
+	if len(e.Method) != 0 {
+		methodText = " " + e.Method
+	}
+
 	instanceText := ""
 	if len(e.Instance) != 0 {
-		instanceText = " for : " + e.Instance
+		instanceText = " for" + methodText + ": " + e.Instance
 	}
 
 	bodyText := e.Body
-	if len(bodyText) == 0 {
+
+	switch {
+	case len(bodyText) == 0 && len(e.rawBody) != 0:
+		bodyText = fmt.Sprintf("[%d bytes of binary body]", len(e.rawBody))
+	case len(bodyText) == 0:
 		bodyText = "[no body]"
+	case e.BodyTruncated:
+		bodyText += "[truncated]"
 	}
 
 	return fmt.Sprintf("got %d%s: %s: %s", e.StatusCode, instanceText, bodyText, e.Status)
@@ -99,3 +217,43 @@ func (e HTTPError) Is(target error) bool {
 	httpErr, ok := target.(HTTPError)
 	return ok && httpErr.StatusCode == e.StatusCode
 }
+
+// isHumanReadable reports whether a body of the content type can be
+// presented as text.
+func isHumanReadable(contentType string, body []byte) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return utf8.Valid(body)
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"),
+		mediaType == "application/json",
+		mediaType == "application/xml",
+		mediaType == "application/x-www-form-urlencoded":
+		return true
+	default:
+		return false
+	}
+}
+
+// traceID extracts the ID of the distributed trace the request is part of.
+// Similar logic can be found in problems.BasicProblem.DecorateWithRequest.
+func traceID(ctx context.Context) string {
+	if span := opencensus.FromContext(ctx); span != nil {
+		traceID := span.SpanContext().TraceID
+		return strconv.FormatUint(binary.BigEndian.Uint64(traceID[8:]), 10) //nolint: mnd
+	}
+
+	if span, exists := datadog.SpanFromContext(ctx); exists {
+		return strconv.FormatUint(span.Context().TraceID(), 10) //nolint: mnd
+	}
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		return spanContext.TraceID().String()
+	}
+
+	return ""
+}
@@ -6,7 +6,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/go-http-utils/headers"
 	"github.com/stretchr/testify/require"
 
 	"github.com/SKF/go-rest-utility/client"
@@ -35,3 +37,76 @@ func TestClientGet_NotFoundError(t *testing.T) {
 	require.Equal(t, "Not Found", httpErr.Status)
 	require.Equal(t, "a nice description on why teapots are bad", httpErr.Body)
 }
+
+func TestClientGet_ServiceUnavailableError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(headers.ContentType, "text/plain; charset=utf-8")
+		w.Header().Set(headers.RetryAfter, "120")
+		w.Header().Set("X-Correlation-Id", "9b1f0c38")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "down for maintenance, back soon")
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL), client.WithMaxErrorBodySize(4))
+
+	_, err := c.Do(context.Background(), client.Get("endpoint"))
+	require.ErrorIs(t, err, client.ErrServiceUnavailable)
+
+	httpErr := client.HTTPError{}
+	require.ErrorAs(t, err, &httpErr)
+
+	require.Equal(t, http.MethodGet, httpErr.Method)
+	require.Equal(t, "down", httpErr.Body)
+	require.True(t, httpErr.BodyTruncated)
+	require.Equal(t, 2*time.Minute, httpErr.RetryAfter)
+	require.Equal(t, "9b1f0c38", httpErr.CorrelationID)
+	require.Equal(t, "120", httpErr.Meta.Header.Get(headers.RetryAfter))
+	require.True(t, httpErr.IsRetryable())
+	require.Contains(t, httpErr.Error(), "for GET: "+srv.URL+"/endpoint")
+}
+
+func TestClientGet_ErrorWithoutBodyLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(headers.ContentType, "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "database unavailable")
+	}))
+	defer srv.Close()
+
+	for _, size := range []int64{0, -1} {
+		c := client.NewClient(client.WithBaseURL(srv.URL), client.WithMaxErrorBodySize(size))
+
+		_, err := c.Do(context.Background(), client.Get("endpoint"))
+		require.ErrorIs(t, err, client.ErrInternalServerError)
+
+		httpErr := client.HTTPError{}
+		require.ErrorAs(t, err, &httpErr)
+
+		require.Equal(t, "database unavailable", httpErr.Body)
+		require.False(t, httpErr.BodyTruncated)
+	}
+}
+
+func TestClientPost_BinaryError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(headers.ContentType, "application/octet-stream")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte{0x00, 0xFF, 0x10}) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	_, err := c.Do(context.Background(), client.Post("endpoint"))
+	require.ErrorIs(t, err, client.ErrBadGateway)
+
+	httpErr := client.HTTPError{}
+	require.ErrorAs(t, err, &httpErr)
+
+	require.Equal(t, http.MethodPost, httpErr.Method)
+	require.Empty(t, httpErr.Body)
+	require.Equal(t, []byte{0x00, 0xFF, 0x10}, httpErr.RawBody())
+	require.False(t, httpErr.IsRetryable())
+	require.Contains(t, httpErr.Error(), "[3 bytes of binary body]")
+}
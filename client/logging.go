@@ -0,0 +1,463 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SKF/go-utility/v2/log"
+	"github.com/go-http-utils/headers"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const redacted = "[REDACTED]"
+
+// DefaultRedactedHeaders are the headers whose values are never logged.
+var DefaultRedactedHeaders = []string{
+	headers.Authorization,
+	headers.ProxyAuthorization,
+	headers.Cookie,
+	headers.SetCookie,
+	"X-Api-Key",
+}
+
+// DefaultRedactedFields are the names of JSON and form fields whose values are
+// never logged, at any depth and regardless of case.
+var DefaultRedactedFields = []string{
+	"password",
+	"secret",
+	"clientSecret",
+	"token",
+	"accessToken",
+	"refreshToken",
+	"identityToken",
+	"apiKey",
+}
+
+// LoggingConfig configures what the logging middleware logs.
+type LoggingConfig struct {
+	// MaxBodySize is the number of bytes of the request and response bodies
+	// logged at debug level. Zero disables the logging of bodies.
+	MaxBodySize int
+
+	// RedactHeaders are headers to redact in addition to DefaultRedactedHeaders.
+	RedactHeaders []string
+
+	// RedactPaths are dot separated paths of JSON fields to redact in addition
+	// to DefaultRedactedFields, such as "credentials.pin". Arrays are
+	// traversed without being part of the path.
+	RedactPaths []string
+}
+
+// LoggingMiddleware logs the method, URI template, status, duration and
+// sizes of every request. Headers and body snippets are logged at debug level
+// with secrets redacted.
+//
+// A request with a response body is logged once the body has been read to
+// the end or closed, such that streamed responses are never held back, with
+// the number of bytes received and a snippet of what was read, decoded if
+// the body is compressed. The duration is the time until the response header
+// was received.
+//
+// Requests are logged at info level, failed requests at warning level and
+// requests which did not get a response at error level.
+func LoggingMiddleware(logger *slog.Logger, config LoggingConfig) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			debug := logger.Enabled(ctx, slog.LevelDebug)
+
+			attributes := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("url", req.URL.Redacted()),
+				slog.Int64("requestBytes", req.ContentLength),
+			}
+
+			if uriTemplate, ok := URITemplateFromContext(ctx); ok {
+				attributes = append(attributes, slog.String("uriTemplate", uriTemplate))
+			}
+
+			if debug {
+				attributes = append(attributes, slog.Any("requestHeaders", config.redactHeaders(req.Header)))
+
+				if body, ok := config.requestBody(req); ok {
+					attributes = append(attributes, slog.String("requestBody", body))
+				}
+			}
+
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+
+			attributes = append(attributes, slog.Duration("duration", time.Since(start)))
+
+			if err != nil {
+				attributes = append(attributes, slog.String("error", err.Error()))
+				logger.LogAttrs(ctx, slog.LevelError, "http request failed", attributes...)
+
+				return resp, err
+			}
+
+			attributes = append(attributes, slog.Int("status", resp.StatusCode))
+
+			// The header is changed when the body is decompressed, before the
+			// response is logged.
+			var header http.Header
+
+			if debug {
+				header = resp.Header.Clone()
+				attributes = append(attributes, slog.Any("responseHeaders", config.redactHeaders(header)))
+			}
+
+			level := slog.LevelInfo
+			if resp.StatusCode >= http.StatusBadRequest {
+				level = slog.LevelWarn
+			}
+
+			logResponse := func(size int64, snippet []byte, truncated bool) {
+				attributes := append(attributes, slog.Int64("responseBytes", size))
+
+				if body, ok := config.responseBody(header, snippet, truncated); ok {
+					attributes = append(attributes, slog.String("responseBody", body))
+				}
+
+				logger.LogAttrs(ctx, level, "http request", attributes...)
+			}
+
+			if resp.Body == nil || resp.Body == http.NoBody {
+				logResponse(0, nil, false)
+				return resp, nil
+			}
+
+			body := &loggedBody{ReadCloser: resp.Body, log: logResponse}
+			if debug {
+				body.limit = config.MaxBodySize
+			}
+
+			resp.Body = body
+
+			return resp, nil
+		})
+	}
+}
+
+func (config LoggingConfig) redactHeaders(header http.Header) http.Header {
+	header = header.Clone()
+
+	for _, name := range slices.Concat(DefaultRedactedHeaders, config.RedactHeaders) {
+		if _, exists := header[http.CanonicalHeaderKey(name)]; exists {
+			header.Set(name, redacted)
+		}
+	}
+
+	return header
+}
+
+// requestBody returns a snippet of the request body, which is only possible
+// if the body can be replayed without consuming it.
+func (config LoggingConfig) requestBody(req *http.Request) (string, bool) {
+	if config.MaxBodySize <= 0 || req.GetBody == nil || req.Body == nil || req.Body == http.NoBody {
+		return "", false
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return "", false
+	}
+
+	defer body.Close()
+
+	snippet, truncated, err := readSnippet(body, config.MaxBodySize)
+	if err != nil {
+		return "", false
+	}
+
+	return config.bodySnippet(req.Header, snippet, truncated)
+}
+
+// responseBody returns the snippet read of the response body.
+func (config LoggingConfig) responseBody(header http.Header, snippet []byte, truncated bool) (string, bool) {
+	if config.MaxBodySize <= 0 || len(snippet) == 0 {
+		return "", false
+	}
+
+	return config.bodySnippet(header, snippet, truncated)
+}
+
+// bodySnippet decodes and redacts the snippet of a body.
+func (config LoggingConfig) bodySnippet(header http.Header, snippet []byte, truncated bool) (string, bool) {
+	if encoding := header.Get(headers.ContentEncoding); encoding != "" {
+		decoded, decodedTruncated, ok := decodeSnippet(header, snippet, truncated, config.MaxBodySize)
+		if !ok {
+			return "[" + encoding + " encoded body]", true
+		}
+
+		snippet, truncated = decoded, decodedTruncated
+	}
+
+	return config.redactBody(header, snippet, truncated), true
+}
+
+// decodeSnippet decodes the snippet of an encoded body with the registered
+// content decoders, reporting false if it could not be decoded. A truncated
+// snippet is decoded as far as it goes.
+func decodeSnippet(header http.Header, snippet []byte, truncated bool, limit int) ([]byte, bool, bool) {
+	codings := contentCodings(header.Values(headers.ContentEncoding))
+
+	var reader io.Reader = bytes.NewReader(snippet)
+
+	for i := len(codings) - 1; i >= 0; i-- {
+		decoder, found := lookupContentDecoder(codings[i])
+		if !found {
+			return nil, false, false
+		}
+
+		decoded, err := decoder(reader)
+		if err != nil {
+			return nil, false, false
+		}
+
+		defer decoded.Close()
+
+		reader = decoded
+	}
+
+	decoded, err := io.ReadAll(io.LimitReader(reader, int64(limit)+1))
+
+	switch {
+	case len(decoded) > limit:
+		return decoded[:limit], true, true
+	case err != nil && (!truncated || len(decoded) == 0):
+		return nil, false, false
+	default:
+		return decoded, truncated || err != nil, true
+	}
+}
+
+// loggedBody keeps a snippet of the first limit bytes read of a response body
+// and logs the response once the body has been read to the end or closed.
+type loggedBody struct {
+	io.ReadCloser
+
+	log   func(size int64, snippet []byte, truncated bool)
+	limit int
+
+	mu      sync.Mutex
+	size    int64
+	snippet []byte
+	eof     bool
+	logged  bool
+}
+
+func (b *loggedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	b.mu.Lock()
+	b.size += int64(n)
+
+	if room := b.limit + 1 - len(b.snippet); b.limit > 0 && room > 0 {
+		b.snippet = append(b.snippet, p[:min(n, room)]...)
+	}
+
+	b.eof = b.eof || errors.Is(err, io.EOF)
+	b.mu.Unlock()
+
+	if errors.Is(err, io.EOF) {
+		b.done()
+	}
+
+	return n, err
+}
+
+func (b *loggedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.done()
+
+	return err
+}
+
+func (b *loggedBody) done() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.logged {
+		return
+	}
+
+	b.logged = true
+
+	snippet, truncated := b.snippet, !b.eof
+	if len(snippet) > b.limit {
+		snippet, truncated = snippet[:b.limit], true
+	}
+
+	b.log(b.size, snippet, truncated)
+}
+
+func readSnippet(reader io.Reader, size int) ([]byte, bool, error) {
+	snippet, err := io.ReadAll(io.LimitReader(reader, int64(size)+1))
+	if len(snippet) > size {
+		return snippet[:size], true, err
+	}
+
+	return snippet, false, err
+}
+
+// redactBody redacts secrets from JSON and form bodies. Truncated JSON can not
+// be parsed and is therefore never logged.
+func (config LoggingConfig) redactBody(header http.Header, body []byte, truncated bool) string {
+	mediaType, _, _ := mime.ParseMediaType(header.Get(headers.ContentType)) //nolint: errcheck
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var value interface{}
+
+		if truncated || json.Unmarshal(body, &value) != nil {
+			return "[unparsable json body]"
+		}
+
+		redactedBody, _ := json.Marshal(config.redactJSON(value, "")) //nolint: errcheck
+
+		return string(redactedBody)
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if truncated || err != nil {
+			return "[unparsable form body]"
+		}
+
+		for key := range values {
+			if config.isRedactedField(key, key) {
+				values[key] = []string{redacted}
+			}
+		}
+
+		return values.Encode()
+	case !isHumanReadable(mediaType, body):
+		return "[binary body]"
+	case truncated:
+		return string(body) + "[truncated]"
+	default:
+		return string(body)
+	}
+}
+
+func (config LoggingConfig) redactJSON(value interface{}, path string) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+
+			if config.isRedactedField(key, fieldPath) {
+				value[key] = redacted
+			} else {
+				value[key] = config.redactJSON(field, fieldPath)
+			}
+		}
+	case []interface{}:
+		for i, element := range value {
+			value[i] = config.redactJSON(element, path)
+		}
+	}
+
+	return value
+}
+
+func (config LoggingConfig) isRedactedField(name, path string) bool {
+	for _, field := range DefaultRedactedFields {
+		if strings.EqualFold(field, name) {
+			return true
+		}
+	}
+
+	return slices.Contains(config.RedactPaths, path)
+}
+
+// NewGoUtilityHandler returns a slog.Handler writing to a go-utility Logger,
+// such that LoggingMiddleware can log through go-utility as well.
+//
+// The level of a go-utility Logger can not be queried, so debug attributes
+// are always collected and then discarded by the Logger if not enabled.
+func NewGoUtilityHandler(logger log.Logger) slog.Handler {
+	return &goUtilityHandler{logger: logger}
+}
+
+type goUtilityHandler struct {
+	logger log.Logger
+	fields log.Fields
+	prefix string
+}
+
+func (h *goUtilityHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *goUtilityHandler) Handle(ctx context.Context, record slog.Record) error {
+	fields := slices.Clone(h.fields)
+
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = h.appendField(fields, h.prefix, attr)
+		return true
+	})
+
+	h.logger.WithTracing(ctx).CheckWrite(zapLevel(record.Level), record.Message, fields...)
+
+	return nil
+}
+
+func (h *goUtilityHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.fields = slices.Clone(h.fields)
+
+	for _, attr := range attrs {
+		clone.fields = h.appendField(clone.fields, h.prefix, attr)
+	}
+
+	return &clone
+}
+
+func (h *goUtilityHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+
+	return &clone
+}
+
+func (h *goUtilityHandler) appendField(fields log.Fields, prefix string, attr slog.Attr) log.Fields {
+	value := attr.Value.Resolve()
+
+	if value.Kind() == slog.KindGroup {
+		for _, groupAttr := range value.Group() {
+			fields = h.appendField(fields, prefix+attr.Key+".", groupAttr)
+		}
+
+		return fields
+	}
+
+	return append(fields, zap.Any(prefix+attr.Key, value.Any()))
+}
+
+func zapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level >= slog.LevelError:
+		return zapcore.ErrorLevel
+	case level >= slog.LevelWarn:
+		return zapcore.WarnLevel
+	case level >= slog.LevelInfo:
+		return zapcore.InfoLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
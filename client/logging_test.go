@@ -0,0 +1,208 @@
+package client_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SKF/go-utility/v2/log"
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client"
+	"github.com/SKF/go-rest-utility/client/auth"
+)
+
+func TestClientLogging(t *testing.T) {
+	srv := newEchoHTTPServer()
+	defer srv.Close()
+
+	buffer := new(bytes.Buffer)
+	logger := slog.New(slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithLogging(logger, client.LoggingConfig{MaxBodySize: 1024}),
+	)
+
+	response, err := c.Do(context.Background(), client.Get("nodes/{id}").Assign("id", "42"))
+	require.NoError(t, err)
+	require.NoError(t, response.Close())
+
+	entry := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+
+	require.Equal(t, "http request", entry["msg"])
+	require.Equal(t, http.MethodGet, entry["method"])
+	require.Equal(t, "nodes/{id}", entry["uriTemplate"])
+	require.Equal(t, srv.URL+"/nodes/42", entry["url"])
+	require.InDelta(t, http.StatusOK, entry["status"], 0)
+	require.Contains(t, entry, "duration")
+	require.NotContains(t, entry, "requestHeaders")
+	require.NotContains(t, entry, "responseBody")
+}
+
+func TestClientLogging_DebugRedactsSecrets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(headers.ContentType, "application/json")
+		w.Write([]byte(`{"tokens":{"accessToken":"eyJ-access"},"label":"signed in"}`)) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	buffer := new(bytes.Buffer)
+	logger := slog.New(slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithLogging(logger, client.LoggingConfig{
+			MaxBodySize: 4096,
+			RedactPaths: []string{"device.pin"},
+		}),
+	)
+
+	payload := map[string]interface{}{
+		"signIn": auth.SignInRequest{Username: "operator", Password: "hunter2"},
+		"device": map[string]interface{}{"pin": 1234, "name": "pump"},
+	}
+
+	request := client.Post("sign-in").
+		SetHeader(headers.Authorization, "Bearer very-secret").
+		WithJSONPayload(payload)
+
+	response, err := c.Do(context.Background(), request)
+	require.NoError(t, err)
+	require.NoError(t, response.Close())
+
+	logged := buffer.String()
+	require.NotContains(t, logged, "hunter2")
+	require.NotContains(t, logged, "very-secret")
+	require.NotContains(t, logged, "1234")
+	require.NotContains(t, logged, "eyJ-access")
+	require.Contains(t, logged, "operator")
+	require.Contains(t, logged, "pump")
+
+	entry := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+
+	require.JSONEq(t,
+		`{"device":{"name":"pump","pin":"[REDACTED]"},"signIn":{"username":"operator","password":"[REDACTED]"}}`,
+		entry["requestBody"].(string),
+	)
+	require.JSONEq(t,
+		`{"tokens":{"accessToken":"[REDACTED]"},"label":"signed in"}`,
+		entry["responseBody"].(string),
+	)
+}
+
+func TestClientLogging_CompressedResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(headers.ContentType, "application/json")
+		w.Header().Set(headers.ContentEncoding, "gzip")
+
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte(`{"accessToken":"eyJ-access","label":"signed in"}`)) //nolint: errcheck
+		gz.Close()
+	}))
+	defer srv.Close()
+
+	buffer := new(bytes.Buffer)
+	logger := slog.New(slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithLogging(logger, client.LoggingConfig{MaxBodySize: 4096}),
+	)
+
+	response, err := c.Do(context.Background(), client.Get("session"))
+	require.NoError(t, err)
+	require.NoError(t, response.Close())
+
+	require.NotContains(t, buffer.String(), "eyJ-access")
+
+	entry := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+
+	require.JSONEq(t, `{"accessToken":"[REDACTED]","label":"signed in"}`, entry["responseBody"].(string))
+}
+
+func TestClientLogging_ChunkedResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(headers.ContentType, "text/plain")
+
+		for range 3 {
+			w.Write([]byte("chunk ")) //nolint: errcheck
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer srv.Close()
+
+	buffer := new(bytes.Buffer)
+	logger := slog.New(slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithLogging(logger, client.LoggingConfig{MaxBodySize: 8}),
+	)
+
+	response, err := c.Do(context.Background(), client.Get("chunks"))
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), response.ContentLength)
+	require.Empty(t, buffer.String())
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.Equal(t, "chunk chunk chunk ", string(body))
+	require.NoError(t, response.Close())
+
+	entry := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+
+	require.InDelta(t, len(body), entry["responseBytes"], 0)
+	require.Equal(t, "chunk ch[truncated]", entry["responseBody"])
+}
+
+func TestClientLogging_EventStream(t *testing.T) {
+	srv := newEndlessHTTPServer(t, "text/event-stream", "data: first\n\n", ": keep-alive\n\n")
+
+	buffer := new(bytes.Buffer)
+	logger := slog.New(slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithLogging(logger, client.LoggingConfig{MaxBodySize: 1024}),
+	)
+
+	requireReturns(t, func() {
+		for event, err := range c.Subscribe(context.Background(), client.Get("events")) {
+			require.NoError(t, err)
+			require.Equal(t, "first", event.Data)
+
+			break
+		}
+	})
+
+	entry := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+
+	require.InDelta(t, http.StatusOK, entry["status"], 0)
+	require.Contains(t, entry["responseBody"], "data: first")
+}
+
+func TestGoUtilityHandler(t *testing.T) {
+	handler := client.NewGoUtilityHandler(log.Nop()).
+		WithAttrs([]slog.Attr{slog.String("service", "hierarchy")}).
+		WithGroup("http")
+
+	logger := slog.New(handler)
+
+	require.True(t, logger.Enabled(context.Background(), slog.LevelDebug))
+	require.NotPanics(t, func() {
+		logger.Info("http request", slog.Int("status", http.StatusOK), slog.Group("timing", slog.Int("ms", 10)))
+	})
+}
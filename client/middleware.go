@@ -0,0 +1,117 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-http-utils/headers"
+	oc_http "go.opencensus.io/plugin/ochttp"
+	dd_http "gopkg.in/DataDog/dd-trace-go.v1/contrib/net/http"
+
+	"github.com/SKF/go-rest-utility/client/auth"
+)
+
+// RoundTripperFunc is an adapter to allow the use of ordinary functions as
+// http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+// Middleware intercepts the requests sent by the Client by wrapping the
+// next http.RoundTripper in the chain.
+//
+// As required by http.RoundTripper a Middleware must not modify the request
+// it is given, instead it should pass a clone to the next RoundTripper.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// chainMiddlewares wraps the transport in the middlewares such that the
+// first middleware is the outermost and sees every request first.
+func chainMiddlewares(transport http.RoundTripper, middlewares []Middleware) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		transport = middlewares[i](transport)
+	}
+
+	return transport
+}
+
+// OpenCensusMiddleware injects OpenCensus trace-headers into every request.
+// Spans are named by ResourceName.
+func OpenCensusMiddleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &oc_http.Transport{
+			Base:           next,
+			FormatSpanName: ResourceName,
+		}
+	}
+}
+
+// DatadogMiddleware creates a Datadog span for, and injects trace-headers
+// into, every request. Resources are named by ResourceName unless
+// overridden with dd_http.RTWithResourceNamer.
+func DatadogMiddleware(opts ...dd_http.RoundTripperOption) Middleware {
+	opts = append([]dd_http.RoundTripperOption{
+		dd_http.RTWithResourceNamer(ResourceName),
+	}, opts...)
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return dd_http.WrapRoundTripper(next, opts...)
+	}
+}
+
+// TokenMiddleware authorizes every request with a token from the provider.
+//
+// The token is not sent along when a redirect leads to another host.
+func TokenMiddleware(provider auth.TokenProvider) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if provider == nil || !isSameHostAsOriginal(req) {
+				return next.RoundTrip(req)
+			}
+
+			token, err := provider.GetRawToken(req.Context())
+			if err != nil {
+				closeRequestBody(req)
+				return nil, tokenError{err}
+			}
+
+			req = req.Clone(req.Context())
+			req.Header.Set(headers.Authorization, token.String())
+
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// tokenError is the failure of a TokenProvider to provide a token, which is
+// never retried as it would only repeat the sign in.
+type tokenError struct {
+	err error
+}
+
+func (e tokenError) Error() string { return "unable to get token: " + e.err.Error() }
+func (e tokenError) Unwrap() error { return e.err }
+
+// isSameHostAsOriginal reports whether a request created by following a
+// redirect still targets the host of the original request.
+func isSameHostAsOriginal(req *http.Request) bool {
+	original := req
+	for original.Response != nil && original.Response.Request != nil {
+		original = original.Response.Request
+	}
+
+	return strings.EqualFold(original.URL.Host, req.URL.Host)
+}
+
+// closeRequestBody closes the body of a request which will not be sent, as
+// required by http.RoundTripper.
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close() //nolint: errcheck
+	}
+}
@@ -0,0 +1,101 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client"
+	"github.com/SKF/go-rest-utility/client/auth"
+)
+
+// testToken is a JWT expiring in year 2100, as required by auth.CachedTokenProvider.
+const testToken = auth.RawToken("e30.eyJleHAiOjQxMDI0NDQ4MDB9.c2lnbmF0dXJl")
+
+func TestClientMiddleware_Order(t *testing.T) {
+	srv := newEchoHTTPServer()
+	defer srv.Close()
+
+	var calls []string
+
+	recorder := func(name string) client.Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return client.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" request")
+				resp, err := next.RoundTrip(req)
+				calls = append(calls, name+" response")
+
+				return resp, err
+			})
+		}
+	}
+
+	transport := client.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls = append(calls, "transport")
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithMiddleware(recorder("first")),
+		client.WithCustomTransport(transport),
+		client.WithMiddleware(recorder("second")),
+		client.WithOpenCensusTracing(),
+		client.WithDatadogTracing(),
+	)
+
+	response, err := c.Do(context.Background(), client.Get("endpoint"))
+	require.NoError(t, err)
+	require.NoError(t, response.Close())
+
+	require.Equal(t, []string{
+		"first request",
+		"second request",
+		"transport",
+		"second response",
+		"first response",
+	}, calls)
+}
+
+func TestClientMiddleware_TokenProvider(t *testing.T) {
+	srv := newEchoHTTPServer()
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithTokenProvider(testToken),
+	)
+
+	echo := RequestEcho{}
+
+	err := c.DoAndUnmarshal(context.Background(), client.Get("endpoint"), &echo)
+	require.NoError(t, err)
+	require.Equal(t, testToken.String(), echo.Header.Get(headers.Authorization))
+}
+
+func TestClientMiddleware_TokenNotSentToOtherHost(t *testing.T) {
+	other := newEchoHTTPServer()
+	defer other.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, testToken.String(), r.Header.Get(headers.Authorization))
+		http.Redirect(w, r, other.URL+"/endpoint", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithTokenProvider(testToken),
+	)
+
+	echo := RequestEcho{}
+
+	err := c.DoAndUnmarshal(context.Background(), client.Get("endpoint"), &echo)
+	require.NoError(t, err)
+	require.Equal(t, "/endpoint", echo.URL)
+	require.Empty(t, echo.Header.Get(headers.Authorization))
+}
@@ -0,0 +1,191 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-http-utils/headers"
+)
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// multipartPayload streams a multipart/form-data body through a pipe, such
+// that large files are never buffered in memory.
+type multipartPayload struct {
+	boundary string
+	parts    []multipartPart
+	opened   atomic.Bool
+}
+
+type multipartPart struct {
+	name     string
+	filename string
+	value    string
+	file     io.Reader
+	start    int64
+}
+
+func newMultipartPayload() *multipartPayload {
+	return &multipartPayload{
+		boundary: multipart.NewWriter(io.Discard).Boundary(),
+	}
+}
+
+// clone returns a copy of the payload which parts can be added to without
+// affecting the original.
+func (mp *multipartPayload) clone() payload {
+	clone := &multipartPayload{
+		boundary: mp.boundary,
+		parts:    slices.Clone(mp.parts),
+	}
+
+	clone.opened.Store(mp.opened.Load())
+
+	return clone
+}
+
+func (mp *multipartPayload) contentType() string {
+	return mime.FormatMediaType("multipart/form-data", map[string]string{"boundary": mp.boundary})
+}
+
+func (mp *multipartPayload) open() (io.ReadCloser, error) {
+	if mp.opened.Swap(true) && !mp.replayable() {
+		return nil, fmt.Errorf("failed to open multipart payload: %w", ErrPayloadConsumed)
+	}
+
+	for _, part := range mp.parts {
+		if seeker, ok := part.file.(io.Seeker); ok {
+			if _, err := seeker.Seek(part.start, io.SeekStart); err != nil {
+				return nil, fmt.Errorf("failed to rewind multipart file: %w", err)
+			}
+		}
+	}
+
+	reader, writer := io.Pipe()
+
+	go func() {
+		writer.CloseWithError(mp.write(writer))
+	}()
+
+	return reader, nil
+}
+
+func (mp *multipartPayload) write(w io.Writer) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(mp.boundary); err != nil {
+		return err
+	}
+
+	for _, part := range mp.parts {
+		if part.file == nil {
+			if err := mw.WriteField(part.name, part.value); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		writer, err := mw.CreatePart(part.header())
+		if err != nil {
+			return err
+		}
+
+		if _, err = io.Copy(writer, part.file); err != nil {
+			return err
+		}
+	}
+
+	return mw.Close()
+}
+
+func (mp *multipartPayload) contentLength() int64 { return -1 }
+
+func (mp *multipartPayload) replayable() bool {
+	for _, part := range mp.parts {
+		if _, ok := part.file.(io.Seeker); part.file != nil && !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// header is the part header of a file, with the content type guessed from
+// the extension of the filename.
+func (part multipartPart) header() textproto.MIMEHeader {
+	contentType := mime.TypeByExtension(filepath.Ext(part.filename))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set(headers.ContentDisposition, fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		quoteEscaper.Replace(part.name), quoteEscaper.Replace(part.filename)))
+	header.Set(headers.ContentType, contentType)
+
+	return header
+}
+
+// WithMultipart sets the body of the Request to an empty multipart/form-data
+// body, which parts are then added to with Field and File.
+//
+//	client.Post("files").WithMultipart().
+//		Field("description", "vibration data").
+//		File("file", "measurement.csv", file)
+func (r *Request) WithMultipart() *Request {
+	body := newMultipartPayload()
+
+	r.header.Set(headers.ContentType, body.contentType())
+	r.body = body
+
+	return r
+}
+
+// Field adds a form field to the multipart body of the Request, which is
+// started with WithMultipart if the Request has no multipart body yet.
+func (r *Request) Field(name, value string) *Request {
+	body := r.multipart()
+	body.parts = append(body.parts, multipartPart{name: name, value: value})
+
+	return r
+}
+
+// File adds a file to the multipart body of the Request, which is started
+// with WithMultipart if the Request has no multipart body yet.
+//
+// The file is streamed when the Request is sent. If it also implements
+// io.Seeker it will be rewound and sent again on retries and redirects,
+// otherwise the Request can only be sent once.
+func (r *Request) File(name, filename string, file io.Reader) *Request {
+	part := multipartPart{name: name, filename: filename, file: file}
+
+	if seeker, ok := file.(io.Seeker); ok {
+		if start, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			part.start = start
+		} else {
+			part.file = struct{ io.Reader }{file}
+		}
+	}
+
+	body := r.multipart()
+	body.parts = append(body.parts, part)
+
+	return r
+}
+
+func (r *Request) multipart() *multipartPayload {
+	if body, ok := r.body.(*multipartPayload); ok {
+		return body
+	}
+
+	r.WithMultipart()
+
+	return r.body.(*multipartPayload)
+}
@@ -0,0 +1,146 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client"
+	"github.com/SKF/go-rest-utility/client/retry"
+)
+
+type multipartEcho struct {
+	Fields map[string][]string
+	Files  map[string]string
+	Types  map[string]string
+}
+
+// newMultipartHTTPServer echoes the parsed multipart form, after failing the
+// first failures requests with 503 Service Unavailable.
+func newMultipartHTTPServer(failures int32) *httptest.Server {
+	attempts := new(atomic.Int32)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if attempts.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		echo := multipartEcho{
+			Fields: r.MultipartForm.Value,
+			Files:  map[string]string{},
+			Types:  map[string]string{},
+		}
+
+		for name, files := range r.MultipartForm.File {
+			file, _ := files[0].Open()     //nolint: errcheck
+			content, _ := io.ReadAll(file) //nolint: errcheck
+
+			echo.Files[name] = files[0].Filename + ":" + string(content)
+			echo.Types[name] = files[0].Header.Get(headers.ContentType)
+		}
+
+		json.NewEncoder(w).Encode(echo) //nolint: errcheck
+	}))
+}
+
+func TestRequestWithFormPayload(t *testing.T) {
+	srv := newEchoHTTPServer()
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	request := client.Post("sign-in").WithFormPayload(url.Values{
+		"username": {"operator"},
+		"scope":    {"read write"},
+	})
+
+	var echo RequestEcho
+
+	response, err := c.Do(context.Background(), request)
+	require.NoError(t, err)
+	require.NoError(t, response.Unmarshal(&echo))
+
+	require.Equal(t, "application/x-www-form-urlencoded", echo.Header.Get(headers.ContentType))
+	require.Equal(t, "scope=read+write&username=operator", *echo.Body)
+}
+
+func TestRequestWithMultipart(t *testing.T) {
+	srv := newMultipartHTTPServer(0)
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	request := client.Post("files").WithMultipart().
+		Field("description", "vibration data").
+		File("measurement", "measurement.csv", io.MultiReader(strings.NewReader("1,2,3"))).
+		File("attachment", `"quoted".bin`, strings.NewReader("binary"))
+
+	var echo multipartEcho
+
+	response, err := c.Do(context.Background(), request)
+	require.NoError(t, err)
+	require.NoError(t, response.Unmarshal(&echo))
+
+	require.Equal(t, map[string][]string{"description": {"vibration data"}}, echo.Fields)
+	require.Equal(t, map[string]string{
+		"measurement": "measurement.csv:1,2,3",
+		"attachment":  `"quoted".bin:binary`,
+	}, echo.Files)
+	require.Equal(t, "text/csv; charset=utf-8", echo.Types["measurement"])
+	require.Equal(t, "application/octet-stream", echo.Types["attachment"])
+}
+
+func TestRequestWithMultipart_ResentOnRetry(t *testing.T) {
+	srv := newMultipartHTTPServer(1)
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRetry(client.RetryPolicy{
+			Backoff: &retry.ExponentialJitterBackoff{MaxAttempts: 2},
+		}),
+	)
+
+	var echo multipartEcho
+
+	request := client.Put("files").File("measurement", "measurement.csv", strings.NewReader("1,2,3"))
+
+	response, err := c.Do(context.Background(), request)
+	require.NoError(t, err)
+	require.NoError(t, response.Unmarshal(&echo))
+	require.Equal(t, "measurement.csv:1,2,3", echo.Files["measurement"])
+
+	failingSrv := newMultipartHTTPServer(1)
+	defer failingSrv.Close()
+
+	c = client.NewClient(
+		client.WithBaseURL(failingSrv.URL),
+		client.WithRetry(client.RetryPolicy{
+			Backoff: &retry.ExponentialJitterBackoff{MaxAttempts: 2},
+		}),
+	)
+
+	request = client.Put("files").File("measurement", "measurement.csv", io.MultiReader(strings.NewReader("1,2,3")))
+
+	// A file which can not be rewound is not retried, nor sent again.
+	_, err = c.Do(context.Background(), request)
+	require.ErrorIs(t, err, client.ErrServiceUnavailable)
+
+	_, err = c.Do(context.Background(), request)
+	require.ErrorIs(t, err, client.ErrPayloadConsumed)
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const openTelemetryScope = "github.com/SKF/go-rest-utility/client"
+
+// OpenTelemetryConfig configures the OpenTelemetry instrumentation of the client.
+type OpenTelemetryConfig struct {
+	// TracerProvider creates the client spans, defaults to the global
+	// TracerProvider.
+	TracerProvider trace.TracerProvider
+	// MeterProvider records the request duration histogram, defaults to the
+	// global MeterProvider.
+	MeterProvider metric.MeterProvider
+	// Propagator injects the trace context into the request headers,
+	// defaults to W3C `traceparent` and `baggage`.
+	Propagator propagation.TextMapPropagator
+}
+
+// requestDurationBuckets are the bucket boundaries, in seconds, recommended
+// by the HTTP semantic conventions.
+var requestDurationBuckets = []float64{
+	0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10,
+}
+
+// OpenTelemetryMiddleware creates a client span, named by ResourceName, for
+// every request following the HTTP semantic conventions, injects the trace
+// context into the request headers and records the
+// `http.client.request.duration` histogram.
+func OpenTelemetryMiddleware(config OpenTelemetryConfig) Middleware {
+	if config.TracerProvider == nil {
+		config.TracerProvider = otel.GetTracerProvider()
+	}
+
+	if config.MeterProvider == nil {
+		config.MeterProvider = otel.GetMeterProvider()
+	}
+
+	if config.Propagator == nil {
+		config.Propagator = propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{},
+			propagation.Baggage{},
+		)
+	}
+
+	tracer := config.TracerProvider.Tracer(openTelemetryScope)
+
+	// An error is only returned for invalid instrument names, which is not
+	// possible here, and a no-op instrument is returned in that case.
+	duration, _ := config.MeterProvider.Meter(openTelemetryScope).Float64Histogram( //nolint: errcheck
+		semconv.HTTPClientRequestDurationName,
+		metric.WithUnit(semconv.HTTPClientRequestDurationUnit),
+		metric.WithDescription(semconv.HTTPClientRequestDurationDescription),
+		metric.WithExplicitBucketBoundaries(requestDurationBuckets...),
+	)
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			attributes := requestAttributes(req)
+
+			ctx, span := tracer.Start(req.Context(), ResourceName(req),
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attributes...),
+				trace.WithAttributes(semconv.URLFull(req.URL.Redacted())),
+			)
+			defer span.End()
+
+			req = req.Clone(ctx)
+			config.Propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			resp, err := next.RoundTrip(req)
+
+			switch {
+			case err != nil:
+				attributes = append(attributes, semconv.ErrorTypeOther)
+
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			case resp.StatusCode >= http.StatusBadRequest:
+				attributes = append(attributes,
+					semconv.HTTPResponseStatusCode(resp.StatusCode),
+					semconv.ErrorTypeKey.String(strconv.Itoa(resp.StatusCode)),
+				)
+
+				span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+			default:
+				attributes = append(attributes, semconv.HTTPResponseStatusCode(resp.StatusCode))
+			}
+
+			span.SetAttributes(attributes...)
+			duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attributes...))
+
+			return resp, err
+		})
+	}
+}
+
+func requestAttributes(req *http.Request) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.ServerAddress(req.URL.Hostname()),
+	}
+
+	if port := serverPort(req); port > 0 {
+		attributes = append(attributes, semconv.ServerPort(port))
+	}
+
+	if uriTemplate, ok := URITemplateFromContext(req.Context()); ok && uriTemplate != "" {
+		attributes = append(attributes, semconv.URLTemplate(uriTemplate))
+	}
+
+	return attributes
+}
+
+func serverPort(req *http.Request) int {
+	if _, port, err := net.SplitHostPort(req.URL.Host); err == nil {
+		if number, err := strconv.Atoi(port); err == nil {
+			return number
+		}
+	}
+
+	switch req.URL.Scheme {
+	case "http":
+		return 80 //nolint: mnd
+	case "https":
+		return 443 //nolint: mnd
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,88 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/SKF/go-rest-utility/client"
+)
+
+func TestClientOpenTelemetry(t *testing.T) {
+	srv := newEchoHTTPServer()
+	defer srv.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithOpenTelemetry(client.OpenTelemetryConfig{
+			TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)),
+			MeterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+		}),
+	)
+
+	echo := RequestEcho{}
+
+	err := c.DoAndUnmarshal(context.Background(), client.Get("nodes/{id}").Assign("id", "42"), &echo)
+	require.NoError(t, err)
+
+	require.Len(t, spans.Ended(), 1)
+	span := spans.Ended()[0]
+
+	require.Equal(t, "GET /nodes/{id}", span.Name())
+	require.Equal(t, trace.SpanKindClient, span.SpanKind())
+	require.Contains(t, span.Attributes(), attribute.String("url.template", "nodes/{id}"))
+	require.Contains(t, span.Attributes(), attribute.Int("http.response.status_code", http.StatusOK))
+
+	traceparent := echo.Header.Get("Traceparent")
+	require.Contains(t, traceparent, span.SpanContext().TraceID().String())
+	require.Contains(t, traceparent, span.SpanContext().SpanID().String())
+
+	metrics := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &metrics))
+	require.Len(t, metrics.ScopeMetrics, 1)
+	require.Len(t, metrics.ScopeMetrics[0].Metrics, 1)
+
+	duration := metrics.ScopeMetrics[0].Metrics[0]
+	require.Equal(t, "http.client.request.duration", duration.Name)
+
+	histogram, ok := duration.Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, histogram.DataPoints, 1)
+	require.Equal(t, uint64(1), histogram.DataPoints[0].Count)
+
+	template, found := histogram.DataPoints[0].Attributes.Value("url.template")
+	require.True(t, found)
+	require.Equal(t, "nodes/{id}", template.AsString())
+}
+
+func TestClientOpenTelemetry_ErrorCorrelationID(t *testing.T) {
+	srv, _ := newFlakyHTTPServer(1, http.StatusInternalServerError)
+	defer srv.Close()
+
+	spans := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithOpenTelemetry(client.OpenTelemetryConfig{TracerProvider: provider}),
+	)
+
+	_, err := c.Do(context.Background(), client.Get("nodes"))
+
+	httpErr := client.HTTPError{}
+	require.ErrorAs(t, err, &httpErr)
+
+	require.Len(t, spans.Ended(), 1)
+	require.Equal(t, spans.Ended()[0].SpanContext().TraceID().String(), httpErr.CorrelationID)
+}
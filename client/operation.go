@@ -0,0 +1,258 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-http-utils/headers"
+
+	"github.com/SKF/go-rest-utility/client/retry"
+	"github.com/SKF/go-rest-utility/problems"
+)
+
+const (
+	// DefaultOperationPollBase and DefaultOperationPollCap configure the
+	// backoff between polls when an OperationPolicy has no Backoff.
+	DefaultOperationPollBase = 500 * time.Millisecond
+	DefaultOperationPollCap  = 30 * time.Second
+)
+
+var (
+	ErrMissingOperationLocation = errors.New("accepted response is missing a location")
+	ErrOperationFailed          = errors.New("operation failed")
+)
+
+// Operation is the status resource of a long-running operation, as returned
+// by a single poll.
+type Operation struct {
+	// URL is the URL of the status resource.
+	URL  *url.URL
+	Meta *ResponseMeta
+	Body []byte
+
+	decoder ProblemDecoder
+	ctx     context.Context //nolint: containedctx
+}
+
+// Problem decodes a problem embedded in the status resource with the
+// ProblemDecoder of the Client.
+func (op *Operation) Problem(raw json.RawMessage) (problems.Problem, error) {
+	decoder := op.decoder
+	if decoder == nil {
+		decoder = &BasicProblemDecoder{}
+	}
+
+	resp := &http.Response{
+		Header: http.Header{headers.ContentType: {problems.ContentType}},
+		Body:   io.NopCloser(bytes.NewReader(raw)),
+	}
+
+	return decoder.DecodeProblem(op.ctx, resp)
+}
+
+// OperationPredicate decides whether the operation has reached a terminal
+// state. An operation which has failed is reported with a non-nil error,
+// usually the problem embedded in the status resource.
+type OperationPredicate func(op *Operation) (done bool, err error)
+
+// OperationPolicy describes how AwaitOperation polls the status resource.
+type OperationPolicy struct {
+	// Backoff provides the delay before each poll, unless the `Retry-After`
+	// header of the previous response asks for a longer one. Polling stops
+	// when it returns retry.ErrBackoffExhausted. Defaults to an exponential
+	// backoff between DefaultOperationPollBase and DefaultOperationPollCap.
+	Backoff retry.BackoffProvider
+
+	// Done decides whether the operation has completed, defaults to
+	// StatusFieldPredicate.
+	Done OperationPredicate
+}
+
+// StatusFieldPredicate is the default OperationPredicate. A `303 See Other`
+// response completes the operation, as does a status resource whose "status"
+// field is "succeeded" or "completed". When the field is "failed", "canceled"
+// or "cancelled" the problem in the "error" field is returned, or
+// ErrOperationFailed if there is none. Fields are compared case-insensitively.
+func StatusFieldPredicate(op *Operation) (bool, error) {
+	if op.Meta.StatusCode == http.StatusSeeOther {
+		return true, nil
+	}
+
+	var status struct {
+		Status string          `json:"status"`
+		Error  json.RawMessage `json:"error"`
+	}
+
+	if err := json.Unmarshal(op.Body, &status); err != nil {
+		return false, fmt.Errorf("failed to json decode operation status: %w", err)
+	}
+
+	switch strings.ToLower(status.Status) {
+	case "succeeded", "completed":
+		return true, nil
+	case "failed", "canceled", "cancelled":
+		if len(status.Error) == 0 || string(status.Error) == "null" {
+			return true, fmt.Errorf("%w: %s", ErrOperationFailed, status.Status)
+		}
+
+		problem, err := op.Problem(status.Error)
+		if err != nil {
+			return true, fmt.Errorf("%w: %s: %w", ErrOperationFailed, status.Status, err)
+		}
+
+		return true, problem
+	default:
+		return false, nil
+	}
+}
+
+// AwaitOperation executes the request and, if the server responds with
+// `202 Accepted`, polls the status resource in its `Location` header until
+// the operation is done. Any other response is decoded directly.
+//
+// Once done, the final resource is fetched from the `Location` header of the
+// status resource, such as the one of a `303 See Other`. Without one, the
+// status resource itself is decoded into a value of type T.
+//
+//	job, meta, err := client.AwaitOperation[Job](ctx, c, client.Post("jobs").WithJSONPayload(spec), client.OperationPolicy{})
+func AwaitOperation[T any](ctx context.Context, c *Client, r *Request, policy OperationPolicy) (T, *ResponseMeta, error) {
+	var v T
+
+	response, err := c.Do(ctx, r)
+	if err != nil {
+		return v, nil, err
+	}
+
+	if response.StatusCode != http.StatusAccepted {
+		defer response.Close()
+		return decodeResponse[T](response)
+	}
+
+	meta := newResponseMeta(response)
+	discardResponse(&response.Response)
+
+	statusURL, err := resolveLocation(response.Request.URL, meta.Location)
+	if err != nil {
+		return v, meta, err
+	}
+
+	op, err := c.pollOperation(ctx, r, statusURL, meta.Header, policy)
+	if err != nil {
+		return v, meta, err
+	}
+
+	if op.Meta.Location == "" {
+		if len(op.Body) == 0 {
+			return v, op.Meta, nil
+		}
+
+		if err := json.Unmarshal(op.Body, &v); err != nil {
+			return v, op.Meta, fmt.Errorf("failed to json decode operation result: %w", err)
+		}
+
+		return v, op.Meta, nil
+	}
+
+	resultURL, err := resolveLocation(op.URL, op.Meta.Location)
+	if err != nil {
+		return v, op.Meta, err
+	}
+
+	return DoJSON[T](ctx, c, locationRequest(r, resultURL))
+}
+
+// locationRequest returns a GET request of the URL of a `Location` header,
+// which is requested as is, named after the URI template of r such that
+// every operation is named the same.
+func locationRequest(r *Request, location *url.URL) *Request {
+	request := Get(r.uriTemplate)
+	request.url = location
+
+	return request
+}
+
+// pollOperation requests the status resource until the predicate of the
+// policy decides that the operation is done.
+func (c *Client) pollOperation(ctx context.Context, r *Request, statusURL *url.URL, header http.Header, policy OperationPolicy) (*Operation, error) {
+	backoff := policy.Backoff
+	if backoff == nil {
+		backoff = &retry.ExponentialJitterBackoff{Base: DefaultOperationPollBase, Cap: DefaultOperationPollCap}
+	}
+
+	done := policy.Done
+	if done == nil {
+		done = StatusFieldPredicate
+	}
+
+	for attempt := 1; ; attempt++ {
+		delay, err := backoff.BackoffByAttempt(attempt)
+		if err != nil {
+			return nil, fmt.Errorf("failed awaiting operation: %w", err)
+		}
+
+		if retryAfter, ok := parseRetryAfter(header, time.Now()); ok && retryAfter > delay {
+			delay = retryAfter
+		}
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, fmt.Errorf("waiting to poll operation: %w", err)
+		}
+
+		op, err := c.fetchOperation(ctx, r, statusURL)
+		if err != nil {
+			return nil, err
+		}
+
+		completed, err := done(op)
+		if completed || err != nil {
+			return op, err
+		}
+
+		header = op.Meta.Header
+	}
+}
+
+func (c *Client) fetchOperation(ctx context.Context, r *Request, statusURL *url.URL) (*Operation, error) {
+	response, err := c.Do(ctx, locationRequest(r, statusURL).WithFollowRedirects(false))
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read operation status: %w", err)
+	}
+
+	return &Operation{
+		URL:     response.Request.URL,
+		Meta:    newResponseMeta(response),
+		Body:    body,
+		decoder: c.problemDecoder,
+		ctx:     ctx,
+	}, nil
+}
+
+// resolveLocation resolves a `Location` header against the URL it was
+// returned from.
+func resolveLocation(base *url.URL, location string) (*url.URL, error) {
+	if location == "" {
+		return nil, ErrMissingOperationLocation
+	}
+
+	target, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid operation location: %w", err)
+	}
+
+	return base.ResolveReference(target), nil
+}
@@ -0,0 +1,238 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client"
+	"github.com/SKF/go-rest-utility/client/retry"
+	"github.com/SKF/go-rest-utility/problems"
+)
+
+type job struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// newOperationHTTPServer accepts jobs at /jobs, which are running for the
+// first polls of /operations/1 and then reply with the final status.
+func newOperationHTTPServer(polls int32, final func(w http.ResponseWriter)) (*httptest.Server, *atomic.Int32) {
+	attempts := new(atomic.Int32)
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("POST /jobs", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(headers.Location, "/operations/1")
+		w.Header().Set(headers.RetryAfter, "0")
+		w.WriteHeader(http.StatusAccepted)
+	})
+	handler.HandleFunc("GET /operations/1", func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) < polls {
+			fmt.Fprint(w, `{"status": "Running"}`)
+			return
+		}
+
+		final(w)
+	})
+	handler.HandleFunc("GET /jobs/1", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"id": "1", "status": "done"}`)
+	})
+
+	return httptest.NewServer(handler), attempts
+}
+
+func TestAwaitOperation_Location(t *testing.T) {
+	srv, attempts := newOperationHTTPServer(3, func(w http.ResponseWriter) {
+		w.Header().Set(headers.Location, "../jobs/1")
+		fmt.Fprint(w, `{"status": "Succeeded"}`)
+	})
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	result, meta, err := client.AwaitOperation[job](context.Background(), c, client.Post("jobs"), client.OperationPolicy{
+		Backoff: &retry.ExponentialJitterBackoff{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, meta.StatusCode)
+	require.Equal(t, job{ID: "1", Status: "done"}, result)
+	require.Equal(t, int32(3), attempts.Load())
+}
+
+func TestAwaitOperation_SeeOther(t *testing.T) {
+	srv, _ := newOperationHTTPServer(2, func(w http.ResponseWriter) {
+		w.Header().Set(headers.Location, "/jobs/1")
+		w.WriteHeader(http.StatusSeeOther)
+	})
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	result, _, err := client.AwaitOperation[job](context.Background(), c, client.Post("jobs"), client.OperationPolicy{
+		Backoff: &retry.ExponentialJitterBackoff{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, job{ID: "1", Status: "done"}, result)
+}
+
+func TestAwaitOperation_StatusResult(t *testing.T) {
+	srv, _ := newOperationHTTPServer(1, func(w http.ResponseWriter) {
+		fmt.Fprint(w, `{"id": "1", "status": "completed"}`)
+	})
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	result, _, err := client.AwaitOperation[job](context.Background(), c, client.Post("jobs"), client.OperationPolicy{
+		Backoff: &retry.ExponentialJitterBackoff{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, job{ID: "1", Status: "completed"}, result)
+}
+
+func TestAwaitOperation_Failed(t *testing.T) {
+	srv, _ := newOperationHTTPServer(2, func(w http.ResponseWriter) {
+		fmt.Fprint(w, `{"status": "Failed", "error": {
+			"type": "/problems/quota-exceeded",
+			"title": "Quota exceeded",
+			"status": 422,
+			"limit": 10
+		}}`)
+	})
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	_, _, err := client.AwaitOperation[job](context.Background(), c, client.Post("jobs"), client.OperationPolicy{
+		Backoff: &retry.ExponentialJitterBackoff{},
+	})
+
+	var problem client.UnknownProblem
+
+	require.ErrorAs(t, err, &problem)
+	require.Equal(t, "Quota exceeded", problem.Title)
+	require.JSONEq(t, "10", string(problem.Extensions["limit"]))
+
+	var basic problems.BasicProblem
+
+	require.ErrorAs(t, err, &basic)
+}
+
+func TestAwaitOperation_FailedWithoutProblem(t *testing.T) {
+	srv, _ := newOperationHTTPServer(1, func(w http.ResponseWriter) {
+		fmt.Fprint(w, `{"status": "cancelled"}`)
+	})
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	_, _, err := client.AwaitOperation[job](context.Background(), c, client.Post("jobs"), client.OperationPolicy{
+		Backoff: &retry.ExponentialJitterBackoff{},
+	})
+	require.ErrorIs(t, err, client.ErrOperationFailed)
+}
+
+func TestAwaitOperation_CustomPredicate(t *testing.T) {
+	srv, attempts := newOperationHTTPServer(100, nil)
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	errAborted := errors.New("aborted")
+
+	_, _, err := client.AwaitOperation[job](context.Background(), c, client.Post("jobs"), client.OperationPolicy{
+		Backoff: &retry.ExponentialJitterBackoff{},
+		Done: func(op *client.Operation) (bool, error) {
+			require.Equal(t, "/operations/1", op.URL.Path)
+
+			if attempts.Load() == 4 {
+				return true, errAborted
+			}
+
+			return false, nil
+		},
+	})
+	require.ErrorIs(t, err, errAborted)
+	require.Equal(t, int32(4), attempts.Load())
+}
+
+func TestAwaitOperation_BackoffExhausted(t *testing.T) {
+	srv, attempts := newOperationHTTPServer(100, nil)
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	_, _, err := client.AwaitOperation[job](context.Background(), c, client.Post("jobs"), client.OperationPolicy{
+		Backoff: &retry.ExponentialJitterBackoff{MaxAttempts: 3},
+	})
+	require.ErrorIs(t, err, retry.ErrBackoffExhausted)
+	require.Equal(t, int32(3), attempts.Load())
+}
+
+func TestAwaitOperation_Completed(t *testing.T) {
+	srv, attempts := newOperationHTTPServer(1, nil)
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	result, meta, err := client.AwaitOperation[job](context.Background(), c, client.Get("jobs/1"), client.OperationPolicy{})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, meta.StatusCode)
+	require.Equal(t, job{ID: "1", Status: "done"}, result)
+	require.Zero(t, attempts.Load())
+}
+
+func TestAwaitOperation_MissingLocation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	_, meta, err := client.AwaitOperation[job](context.Background(), c, client.Post("jobs"), client.OperationPolicy{})
+	require.ErrorIs(t, err, client.ErrMissingOperationLocation)
+	require.Equal(t, http.StatusAccepted, meta.StatusCode)
+}
+
+func TestAwaitOperation_LocationRequestedAsIs(t *testing.T) {
+	var polled []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			w.Header().Set(headers.Location, "/operations/7?cursor={abc}")
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			polled = append(polled, r.URL.RequestURI())
+			fmt.Fprint(w, `{"id": "7", "status": "Succeeded"}`)
+		}
+	}))
+	defer srv.Close()
+
+	var uriTemplates []string
+
+	c := client.NewClient(client.WithBaseURL(srv.URL), client.WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		return client.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			uriTemplate, _ := client.URITemplateFromContext(req.Context())
+			uriTemplates = append(uriTemplates, uriTemplate)
+
+			return next.RoundTrip(req)
+		})
+	}))
+
+	result, _, err := client.AwaitOperation[job](context.Background(), c, client.Post("jobs"), client.OperationPolicy{
+		Backoff: &retry.ExponentialJitterBackoff{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, job{ID: "7", Status: "Succeeded"}, result)
+	require.Equal(t, []string{"/operations/7?cursor={abc}"}, polled)
+	require.Equal(t, []string{"jobs", "jobs"}, uriTemplates)
+}
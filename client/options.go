@@ -1,15 +1,16 @@
 package client
 
 import (
-	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"time"
 
-	oc_http "go.opencensus.io/plugin/ochttp"
 	dd_http "gopkg.in/DataDog/dd-trace-go.v1/contrib/net/http"
 
 	"github.com/SKF/go-rest-utility/client/auth"
+	"github.com/SKF/go-rest-utility/client/cache"
+	"github.com/SKF/go-rest-utility/client/codec"
 )
 
 type Option func(*Client)
@@ -36,19 +37,128 @@ func WithDefaultHeader(header, value string) Option {
 	}
 }
 
+// WithProblemDecoder replaces the default ProblemRegistry used to decode
+// responses with a problem media type.
 func WithProblemDecoder(decoder ProblemDecoder) Option {
 	return func(c *Client) {
 		c.problemDecoder = decoder
 	}
 }
 
-// WithOpenCensusTracing will add an OpenCensus transport to the client
+// WithoutProblemDecoder disables the decoding of problems, responses with a
+// problem media type are instead returned as an HTTPError.
+func WithoutProblemDecoder() Option {
+	return WithProblemDecoder(nil)
+}
+
+// WithCodecs will use the codecs of the registry to encode payloads set with
+// Request.WithPayloadAs and decode responses in Response.Unmarshal. Unless a
+// default Accept header is set, the registered media types are accepted, with
+// JSON preferred over the others.
+func WithCodecs(registry *codec.Registry) Option {
+	return func(c *Client) {
+		c.codecs = registry
+	}
+}
+
+// WithMaxErrorBodySize limits the number of bytes of an error response body
+// kept in the returned HTTPError. Defaults to DefaultMaxErrorBodySize, a
+// non-positive size disables the limit.
+func WithMaxErrorBodySize(size int64) Option {
+	return func(c *Client) {
+		c.maxErrorBodySize = size
+	}
+}
+
+// WithMaxDecompressedSize limits the size of response bodies after
+// decompression, reading beyond it fails with a DecompressedSizeError.
+// Defaults to DefaultMaxDecompressedSize, a non-positive size disables the limit.
+func WithMaxDecompressedSize(size int64) Option {
+	return func(c *Client) {
+		c.maxDecompressedSize = size
+	}
+}
+
+// WithRequestCompression will compress the request bodies of the client,
+// unless overridden by Request.WithCompression. If a server responds with
+// 415 Unsupported Media Type the request is sent again uncompressed, and no
+// more requests to the host are compressed.
+func WithRequestCompression(compression RequestCompression) Option {
+	return func(c *Client) {
+		c.requestCompression = compression
+	}
+}
+
+// WithRetry will make the client retry requests which fail on connection
+// errors or with a retryable status code, waiting according to the
+// BackoffProvider of the policy or the Retry-After header of the response.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = &policy
+	}
+}
+
+// WithOpenCensusTracing will add an OpenCensus middleware to the client
 // so that it will automatically inject trace-headers.
 //
 // Should be used when you trace your application with OpenCensus.
 func WithOpenCensusTracing() Option {
+	return WithMiddleware(OpenCensusMiddleware())
+}
+
+// WithOpenTelemetry will add an OpenTelemetry middleware to the client so
+// that it creates client spans, injects W3C trace-headers and records request
+// duration metrics.
+//
+// Should be used when you trace your application with OpenTelemetry.
+func WithOpenTelemetry(config OpenTelemetryConfig) Option {
+	return WithMiddleware(OpenTelemetryMiddleware(config))
+}
+
+// WithLogging will add a logging middleware to the client which logs every
+// request with secrets redacted, see LoggingMiddleware.
+//
+// Use NewGoUtilityHandler to log through the go-utility logger:
+//
+//	client.WithLogging(slog.New(client.NewGoUtilityHandler(log.Base())), client.LoggingConfig{})
+func WithLogging(logger *slog.Logger, config LoggingConfig) Option {
+	return WithMiddleware(LoggingMiddleware(logger, config))
+}
+
+// WithRateLimit will add a rate limiting middleware to the client which
+// blocks requests until the rate limit of their host allows them to be sent,
+// see RateLimit.
+func WithRateLimit(limit RateLimit) Option {
+	return WithMiddleware(RateLimitMiddleware(limit))
+}
+
+// WithCircuitBreaker will add the circuit breaker as a middleware to the
+// client. Keep a reference to the CircuitBreaker to query its state.
+func WithCircuitBreaker(cb *CircuitBreaker) Option {
+	return WithMiddleware(cb.Middleware())
+}
+
+// WithDeduplication will let identical concurrent GET and HEAD requests share
+// a single round trip, see DeduplicationMiddleware.
+func WithDeduplication() Option {
+	return WithMiddleware(DeduplicationMiddleware())
+}
+
+// WithCache will cache GET responses in the store, such as a
+// cache.MemoryStore or cache.DiskStore, see CacheMiddleware.
+//
+// The cached responses are those of the TokenProvider of the Client, so the
+// store, or DiskStore directory, must never be shared with a client with other
+// credentials, unless each client scopes it with cache.NewScopedStore.
+func WithCache(store cache.Store) Option {
+	return WithMiddleware(CacheMiddleware(store))
+}
+
+// WithResourceNamer customises how requests are named in traces and metrics,
+// defaults to DefaultResourceNamer.
+func WithResourceNamer(namer ResourceNamer) Option {
 	return func(c *Client) {
-		c.client.Transport = new(oc_http.Transport)
+		c.resourceNamer = namer
 	}
 }
 
@@ -64,7 +174,7 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
-// WithDatadogTracing will add a Datadog transport to the client
+// WithDatadogTracing will add a Datadog middleware to the client
 // so that it will automatically inject trace-headers.
 //
 // Should be used when you trace your application with Datadog.
@@ -75,27 +185,24 @@ func WithTimeout(timeout time.Duration) Option {
 //	    dd_http.RTWithServiceName("<service_name>"),
 //	)
 func WithDatadogTracing(opts ...dd_http.RoundTripperOption) Option {
-	resourceNamer := func(req *http.Request) string {
-		return fmt.Sprintf("%s %s", req.Method, req.URL.String())
-	}
-	opts = append([]dd_http.RoundTripperOption{
-		dd_http.RTWithResourceNamer(resourceNamer),
-	}, opts...)
+	return WithMiddleware(DatadogMiddleware(opts...))
+}
 
+// WithCustomTransport overwrites the default http.RoundTripper transport which
+// sends the requests after they have passed through all middlewares.
+func WithCustomTransport(transport http.RoundTripper) Option {
 	return func(c *Client) {
-		c.client = dd_http.WrapClient(
-			c.client,
-			opts...,
-		)
+		c.transport = transport
 	}
 }
 
-// WithCustomTransport overwrites the default or otherwise configured http.RoundTripper transport
-// on the underlying http.Client.
+// WithMiddleware appends middlewares to the chain every request passes
+// through. Middlewares are applied in the order they are added, the first
+// one sees the request first and the response last.
 //
-// Not that WithOpenCensusTracing also sets a custom http.RoundTripper transport, you may not use both.
-func WithCustomTransport(transport http.RoundTripper) Option {
+// The TokenProvider of the client is always applied after all middlewares.
+func WithMiddleware(middlewares ...Middleware) Option {
 	return func(c *Client) {
-		c.client.Transport = transport
+		c.middlewares = append(c.middlewares, middlewares...)
 	}
 }
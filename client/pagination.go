@@ -0,0 +1,333 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-http-utils/headers"
+)
+
+// Page is a single response of a paginated endpoint.
+type Page struct {
+	// Request is the Request which resulted in this page.
+	Request *Request
+	// URL is the final URL this page was fetched from.
+	URL *url.URL
+
+	Meta *ResponseMeta
+	Body []byte
+}
+
+// PageStrategy describes how the items and the following page are found in a
+// paginated endpoint.
+type PageStrategy interface {
+	// Items extracts the JSON array of items in the page.
+	Items(page *Page) (json.RawMessage, error)
+
+	// Next returns the Request for the page after the given one, or nil if
+	// it was the last page.
+	Next(page *Page) (*Request, error)
+}
+
+// Paginate requests the pages of a paginated endpoint, starting with r, and
+// yields every item decoded as T. Iteration stops on the first error, which
+// is yielded with the zero value of T, or when the context is done.
+//
+//	for node, err := range client.Paginate[Node](ctx, c, client.Get("nodes{?limit}"), client.LinkHeaderPagination{}) {
+//		...
+//	}
+func Paginate[T any](ctx context.Context, c *Client, r *Request, strategy PageStrategy) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		for request := r; request != nil; {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			page, err := c.fetchPage(ctx, request)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			var items []T
+
+			if err := decodePageItems(strategy, page, &items); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if request, err = strategy.Next(page); err != nil {
+				yield(zero, fmt.Errorf("unable to find next page: %w", err))
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) fetchPage(ctx context.Context, r *Request) (*Page, error) {
+	response, err := c.Do(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read page: %w", err)
+	}
+
+	return &Page{
+		Request: r,
+		URL:     response.Request.URL,
+		Meta:    newResponseMeta(response),
+		Body:    body,
+	}, nil
+}
+
+func decodePageItems(strategy PageStrategy, page *Page, v interface{}) error {
+	raw, err := strategy.Items(page)
+	if err != nil {
+		return fmt.Errorf("unable to find page items: %w", err)
+	}
+
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("failed to json decode page items: %w", err)
+	}
+
+	return nil
+}
+
+// pageField returns the raw JSON value of a top-level field in the page body,
+// or the whole body if field is empty.
+func pageField(page *Page, field string) (json.RawMessage, error) {
+	if field == "" {
+		return page.Body, nil
+	}
+
+	if len(page.Body) == 0 {
+		return nil, nil
+	}
+
+	var fields map[string]json.RawMessage
+
+	if err := json.Unmarshal(page.Body, &fields); err != nil {
+		return nil, fmt.Errorf("failed to json decode page: %w", err)
+	}
+
+	return fields[field], nil
+}
+
+// LinkHeaderPagination follows the RFC 8288 `Link` header with the relation
+// type "next" until no such link is present. The links are requested as is,
+// while the requests keep the URI template of the first for naming them.
+type LinkHeaderPagination struct {
+	// ItemsField is the field in the page body holding the items, if empty
+	// the body itself is expected to be a JSON array.
+	ItemsField string
+}
+
+func (p LinkHeaderPagination) Items(page *Page) (json.RawMessage, error) {
+	return pageField(page, p.ItemsField)
+}
+
+func (p LinkHeaderPagination) Next(page *Page) (*Request, error) {
+	next, found := findLink(page.Meta.Header.Values(headers.Link), "next")
+	if !found {
+		return nil, nil
+	}
+
+	nextURL, err := url.Parse(next)
+	if err != nil {
+		return nil, fmt.Errorf("invalid next link: %w", err)
+	}
+
+	if page.URL != nil {
+		nextURL = page.URL.ResolveReference(nextURL)
+	}
+
+	request := page.Request.Clone()
+	request.url = nextURL
+
+	return request, nil
+}
+
+// findLink returns the target of the first link with the relation type rel.
+func findLink(values []string, rel string) (string, bool) {
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			target, params, found := strings.Cut(strings.TrimSpace(link), ";")
+			if !found || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			for _, param := range strings.Split(params, ";") {
+				key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(key, "rel") {
+					continue
+				}
+
+				for _, relType := range strings.Fields(strings.Trim(value, `"`)) {
+					if strings.EqualFold(relType, rel) {
+						return strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">"), true
+					}
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// ContinuationTokenPagination reads a continuation token from the page body
+// and assigns it to a variable of the URI template for the next page, until
+// the token is empty.
+type ContinuationTokenPagination struct {
+	// ItemsField is the field in the page body holding the items,
+	// defaults to "data".
+	ItemsField string
+	// TokenField is the field in the page body holding the continuation token,
+	// defaults to "continuationToken".
+	TokenField string
+	// Variable is the URI template variable to assign the token to,
+	// defaults to "continuationToken".
+	Variable string
+}
+
+const (
+	defaultItemsField        = "data"
+	defaultContinuationToken = "continuationToken"
+)
+
+func (p ContinuationTokenPagination) Items(page *Page) (json.RawMessage, error) {
+	return pageField(page, withDefault(p.ItemsField, defaultItemsField))
+}
+
+func (p ContinuationTokenPagination) Next(page *Page) (*Request, error) {
+	raw, err := pageField(page, withDefault(p.TokenField, defaultContinuationToken))
+	if err != nil || len(raw) == 0 {
+		return nil, err
+	}
+
+	var token string
+	if err := json.Unmarshal(raw, &token); err != nil {
+		return nil, fmt.Errorf("failed to json decode continuation token: %w", err)
+	}
+
+	if token == "" {
+		return nil, nil
+	}
+
+	return page.Request.Clone().Assign(withDefault(p.Variable, defaultContinuationToken), token), nil
+}
+
+// OffsetPagination increments an offset variable of the URI template by the
+// number of items in each page, until a page holds fewer items than Limit.
+// The first Request should assign the same limit, typically with an offset of 0.
+type OffsetPagination struct {
+	// ItemsField is the field in the page body holding the items, if empty
+	// the body itself is expected to be a JSON array.
+	ItemsField string
+	// OffsetVariable is the URI template variable of the offset,
+	// defaults to "offset".
+	OffsetVariable string
+	// LimitVariable is the URI template variable of the page size,
+	// defaults to "limit".
+	LimitVariable string
+	// Limit is the number of items requested per page.
+	Limit int
+}
+
+var (
+	ErrInvalidPageLimit  = errors.New("page limit must be positive")
+	ErrInvalidPageOffset = errors.New("page offset must be an integer")
+)
+
+func (p OffsetPagination) Items(page *Page) (json.RawMessage, error) {
+	return pageField(page, p.ItemsField)
+}
+
+func (p OffsetPagination) Next(page *Page) (*Request, error) {
+	if p.Limit <= 0 {
+		return nil, ErrInvalidPageLimit
+	}
+
+	var items []json.RawMessage
+
+	if err := decodePageItems(p, page, &items); err != nil {
+		return nil, err
+	}
+
+	if len(items) < p.Limit {
+		return nil, nil
+	}
+
+	offsetVariable := withDefault(p.OffsetVariable, "offset")
+
+	offset, err := pageOffset(page.Request.uriVariables[offsetVariable])
+	if err != nil {
+		return nil, err
+	}
+
+	return page.Request.Clone().
+		Assign(offsetVariable, offset+len(items)).
+		Assign(withDefault(p.LimitVariable, "limit"), p.Limit), nil
+}
+
+// pageOffset converts the assigned offset to an int, an unassigned offset is
+// the first page.
+func pageOffset(value interface{}) (int, error) {
+	switch rv := reflect.ValueOf(value); rv.Kind() {
+	case reflect.Invalid:
+		return 0, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(rv.Uint()), nil //nolint: gosec
+	case reflect.String:
+		offset, err := strconv.Atoi(rv.String())
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidPageOffset, rv.String())
+		}
+
+		return offset, nil
+	default:
+		return 0, fmt.Errorf("%w: %T", ErrInvalidPageOffset, value)
+	}
+}
+
+func withDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
+
+// Ensure the strategies implement all methods required by PageStrategy.
+var (
+	_ PageStrategy = LinkHeaderPagination{}
+	_ PageStrategy = ContinuationTokenPagination{}
+	_ PageStrategy = OffsetPagination{}
+)
@@ -0,0 +1,210 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client"
+)
+
+var paginatedItems = []int{1, 2, 3, 4, 5, 6, 7}
+
+func TestPaginate_LinkHeader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		end := min(offset+3, len(paginatedItems))
+
+		if end < len(paginatedItems) {
+			w.Header().Set(headers.Link, fmt.Sprintf(`</items?offset=%d>; rel="next", </items>; rel="first"`, end))
+		}
+
+		json.NewEncoder(w).Encode(paginatedItems[offset:end]) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	actual := collectPages(t, client.Paginate[int](context.Background(), c, client.Get("items"), client.LinkHeaderPagination{}))
+	require.Equal(t, paginatedItems, actual)
+}
+
+func TestPaginate_LinkHeaderKeepsURITemplate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursor := r.URL.Query().Get("cursor")
+		if cursor == "" {
+			w.Header().Set(headers.Link, `</items?cursor={"after":3}>; rel="next"`)
+		}
+
+		json.NewEncoder(w).Encode([]string{cursor}) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	var uriTemplates []string
+
+	c := client.NewClient(client.WithBaseURL(srv.URL), client.WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		return client.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			uriTemplate, _ := client.URITemplateFromContext(req.Context())
+			uriTemplates = append(uriTemplates, uriTemplate)
+
+			return next.RoundTrip(req)
+		})
+	}))
+
+	request := client.Get("items{?cursor}")
+
+	actual := collectPages(t, client.Paginate[string](context.Background(), c, request, client.LinkHeaderPagination{}))
+	require.Equal(t, []string{"", `{"after":3}`}, actual)
+	require.Equal(t, []string{"items{?cursor}", "items{?cursor}"}, uriTemplates)
+}
+
+func TestPaginate_ContinuationToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("continuationToken"))
+		end := min(offset+3, len(paginatedItems))
+
+		token := ""
+		if end < len(paginatedItems) {
+			token = strconv.Itoa(end)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{ //nolint: errcheck
+			"data":              paginatedItems[offset:end],
+			"continuationToken": token,
+		})
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+	request := client.Get("items{?continuationToken}")
+
+	actual := collectPages(t, client.Paginate[int](context.Background(), c, request, client.ContinuationTokenPagination{}))
+	require.Equal(t, paginatedItems, actual)
+}
+
+func TestPaginate_Offset(t *testing.T) {
+	var requested []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.String())
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		end := min(offset+limit, len(paginatedItems))
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"items": paginatedItems[offset:end]}) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+	request := client.Get("items/{kind}{?offset,limit}").
+		Assign("kind", "sensors").
+		Assign("offset", 0).
+		Assign("limit", 3)
+
+	strategy := client.OffsetPagination{ItemsField: "items", Limit: 3}
+
+	actual := collectPages(t, client.Paginate[int](context.Background(), c, request, strategy))
+	require.Equal(t, paginatedItems, actual)
+	require.Equal(t, []string{
+		"/items/sensors?offset=0&limit=3",
+		"/items/sensors?offset=3&limit=3",
+		"/items/sensors?offset=6&limit=3",
+	}, requested)
+}
+
+func TestPaginate_OffsetTypes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		end := min(offset+3, len(paginatedItems))
+
+		json.NewEncoder(w).Encode(paginatedItems[offset:end]) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+	strategy := client.OffsetPagination{Limit: 3}
+
+	for _, offset := range []interface{}{int64(3), uint(3), "3"} {
+		request := client.Get("items{?offset,limit}").Assign("offset", offset).Assign("limit", 3)
+
+		actual := collectPages(t, client.Paginate[int](context.Background(), c, request, strategy))
+		require.Equal(t, paginatedItems[3:], actual, "offset %T", offset)
+	}
+
+	request := client.Get("items{?offset,limit}").Assign("offset", 3.5).Assign("limit", 3)
+
+	for _, err := range client.Paginate[int](context.Background(), c, request, strategy) {
+		if err != nil {
+			require.ErrorIs(t, err, client.ErrInvalidPageOffset)
+			return
+		}
+	}
+
+	t.Fatal("expected an invalid offset error")
+}
+
+func TestPaginate_StopsOnError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set(headers.Link, `<?page=2>; rel="next"`)
+		json.NewEncoder(w).Encode([]int{1}) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	var (
+		items []int
+		err   error
+	)
+
+	for item, itemErr := range client.Paginate[int](context.Background(), c, client.Get("items"), client.LinkHeaderPagination{}) {
+		if itemErr != nil {
+			err = itemErr
+			break
+		}
+
+		items = append(items, item)
+	}
+
+	require.ErrorIs(t, err, client.ErrInternalServerError)
+	require.Equal(t, []int{1}, items)
+}
+
+func TestPaginate_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := client.NewClient()
+
+	for _, err := range client.Paginate[int](ctx, c, client.Get("http://localhost/items"), client.LinkHeaderPagination{}) {
+		require.ErrorIs(t, err, context.Canceled)
+	}
+}
+
+func collectPages[T any](t *testing.T, pages iter.Seq2[T, error]) []T {
+	t.Helper()
+
+	var items []T
+
+	for item, err := range pages {
+		require.NoError(t, err)
+
+		items = append(items, item)
+	}
+
+	return items
+}
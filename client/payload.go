@@ -0,0 +1,203 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/SKF/go-rest-utility/client/codec"
+)
+
+var ErrPayloadConsumed = errors.New("payload can only be sent once")
+
+// BodyFunc returns a new reader of the same request body every time it is
+// called, allowing the body to be sent again on retries and redirects.
+type BodyFunc func() (io.ReadCloser, error)
+
+// payload produces the body of a Request for every attempt of sending it.
+type payload interface {
+	// open returns a reader positioned at the start of the body.
+	open() (io.ReadCloser, error)
+
+	// contentLength returns the size of the body in bytes, or -1 if unknown.
+	// It is only called after a successful call to open.
+	contentLength() int64
+
+	// replayable reports whether open may be called more than once.
+	replayable() bool
+}
+
+type noPayload struct{}
+
+func (noPayload) open() (io.ReadCloser, error) { return http.NoBody, nil }
+func (noPayload) contentLength() int64         { return 0 }
+func (noPayload) replayable() bool             { return true }
+
+// jsonPayload encodes the payload once, on first use, and then serves the
+// buffered result for every attempt.
+type jsonPayload struct {
+	payload interface{}
+
+	once    sync.Once
+	encoded []byte
+	err     error
+}
+
+func (jp *jsonPayload) encode() ([]byte, error) {
+	jp.once.Do(func() {
+		switch payload := jp.payload.(type) {
+		case []byte:
+			jp.encoded = payload
+		case string:
+			jp.encoded = []byte(payload)
+		default:
+			buf := new(bytes.Buffer)
+
+			if err := json.NewEncoder(buf).Encode(payload); err != nil {
+				jp.err = fmt.Errorf("failed to json encode payload: %w", err)
+				return
+			}
+
+			jp.encoded = buf.Bytes()
+		}
+	})
+
+	return jp.encoded, jp.err
+}
+
+func (jp *jsonPayload) open() (io.ReadCloser, error) {
+	encoded, err := jp.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(bytes.NewReader(encoded)), nil
+}
+
+func (jp *jsonPayload) contentLength() int64 { return int64(len(jp.encoded)) }
+func (jp *jsonPayload) replayable() bool     { return true }
+
+type bytesPayload []byte
+
+func (bp bytesPayload) open() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(bp)), nil }
+func (bp bytesPayload) contentLength() int64         { return int64(len(bp)) }
+func (bp bytesPayload) replayable() bool             { return true }
+
+var defaultCodecs = codec.NewRegistry()
+
+// encodingPayload is a payload which is encoded with the codecs of the Client
+// sending it.
+type encodingPayload interface {
+	encode(codecs *codec.Registry) (payload, error)
+}
+
+// codecPayload encodes the payload with the codec of its media type. Outside
+// of a Client the default codecs are used.
+type codecPayload struct {
+	mediaType string
+	payload   interface{}
+}
+
+func (cp codecPayload) encode(codecs *codec.Registry) (payload, error) {
+	encoder, found := codecs.Lookup(cp.mediaType)
+	if !found {
+		return nil, fmt.Errorf("no codec registered for %s", cp.mediaType)
+	}
+
+	buf := new(bytes.Buffer)
+
+	if err := encoder.Encode(buf, cp.payload); err != nil {
+		return nil, fmt.Errorf("failed to encode %s payload: %w", cp.mediaType, err)
+	}
+
+	return bytesPayload(buf.Bytes()), nil
+}
+
+func (cp codecPayload) open() (io.ReadCloser, error) {
+	encoded, err := cp.encode(defaultCodecs)
+	if err != nil {
+		return nil, err
+	}
+
+	return encoded.open()
+}
+
+func (cp codecPayload) contentLength() int64 { return -1 }
+func (cp codecPayload) replayable() bool     { return true }
+
+// seekerPayload rewinds the reader to where it was positioned when the
+// payload was assigned to the Request.
+type seekerPayload struct {
+	reader io.ReadSeeker
+	start  int64
+	size   int64
+}
+
+func newSeekerPayload(reader io.ReadSeeker) (*seekerPayload, error) {
+	start, err := reader.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := reader.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = reader.Seek(start, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return &seekerPayload{reader: reader, start: start, size: end - start}, nil
+}
+
+func (sp *seekerPayload) open() (io.ReadCloser, error) {
+	if _, err := sp.reader.Seek(sp.start, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind payload: %w", err)
+	}
+
+	return io.NopCloser(sp.reader), nil
+}
+
+func (sp *seekerPayload) contentLength() int64 { return sp.size }
+func (sp *seekerPayload) replayable() bool     { return true }
+
+type funcPayload BodyFunc
+
+func (fp funcPayload) open() (io.ReadCloser, error) { return fp() }
+func (fp funcPayload) contentLength() int64         { return -1 }
+func (fp funcPayload) replayable() bool             { return true }
+
+// readerPayload can only be sent once, retries and redirects which need to
+// resend it fail with ErrPayloadConsumed.
+type readerPayload struct {
+	reader io.Reader
+	opened atomic.Bool
+}
+
+func (rp *readerPayload) open() (io.ReadCloser, error) {
+	if rp.opened.Swap(true) {
+		return nil, fmt.Errorf("failed to open payload: %w", ErrPayloadConsumed)
+	}
+
+	if rc, ok := rp.reader.(io.ReadCloser); ok {
+		return rc, nil
+	}
+
+	return io.NopCloser(rp.reader), nil
+}
+
+func (rp *readerPayload) contentLength() int64 {
+	if lener, ok := rp.reader.(interface{ Len() int }); ok {
+		return int64(lener.Len())
+	}
+
+	return -1
+}
+
+func (rp *readerPayload) replayable() bool { return false }
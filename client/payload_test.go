@@ -0,0 +1,185 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client"
+	"github.com/SKF/go-rest-utility/client/retry"
+)
+
+func TestPayload_JSONResentOnTemporaryRedirect(t *testing.T) {
+	srv := newBodyRecordingHTTPServer(func(w http.ResponseWriter, r *http.Request) bool {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusTemporaryRedirect)
+			return true
+		}
+
+		return false
+	})
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	response, err := c.Do(context.Background(), client.Put("old").WithJSONPayload(map[string]int{"amount": 2000}))
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.NoError(t, response.Close())
+
+	require.Equal(t, `{"amount":2000}`, strings.TrimSuffix(string(body), "\n"))
+}
+
+func TestPayload_BufferResentOnTemporaryRedirect(t *testing.T) {
+	srv := newBodyRecordingHTTPServer(func(w http.ResponseWriter, r *http.Request) bool {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusTemporaryRedirect)
+			return true
+		}
+
+		return false
+	})
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	response, err := c.Do(context.Background(), client.Put("old").WithPayload("text/plain", bytes.NewBufferString("hello")))
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.NoError(t, response.Close())
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, "hello", string(body))
+}
+
+func TestPayload_SeekerResentOnRetry(t *testing.T) {
+	attempts := new(atomic.Int32)
+
+	srv := newBodyRecordingHTTPServer(func(w http.ResponseWriter, _ *http.Request) bool {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return true
+		}
+
+		return false
+	})
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRetry(client.RetryPolicy{
+			Backoff: &retry.ExponentialJitterBackoff{MaxAttempts: 3},
+		}),
+	)
+
+	payload := strings.NewReader("measurement data")
+
+	response, err := c.Do(context.Background(), client.Put("endpoint").WithPayload("text/plain", payload))
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.NoError(t, response.Close())
+
+	require.Equal(t, int32(2), attempts.Load())
+	require.Equal(t, "measurement data", string(body))
+}
+
+func TestPayload_FuncResentOnRetry(t *testing.T) {
+	attempts := new(atomic.Int32)
+
+	srv := newBodyRecordingHTTPServer(func(w http.ResponseWriter, _ *http.Request) bool {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return true
+		}
+
+		return false
+	})
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRetry(client.RetryPolicy{
+			Backoff: &retry.ExponentialJitterBackoff{MaxAttempts: 3},
+		}),
+	)
+
+	opened := 0
+	request := client.Put("endpoint").WithPayloadFunc("text/plain", func() (io.ReadCloser, error) {
+		opened++
+		return io.NopCloser(strings.NewReader("generated")), nil
+	})
+
+	response, err := c.Do(context.Background(), request)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.NoError(t, response.Close())
+
+	require.Equal(t, 2, opened)
+	require.Equal(t, "generated", string(body))
+}
+
+// newBodyRecordingHTTPServer returns a server which responds with the body of
+// the request, unless intercept has already written a response.
+func newBodyRecordingHTTPServer(intercept func(http.ResponseWriter, *http.Request) bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if intercept(w, r) {
+			return
+		}
+
+		w.Write(body) //nolint: errcheck
+	}))
+}
+
+func TestPayload_ReaderNotResentOnRetry(t *testing.T) {
+	attempts := new(atomic.Int32)
+	bodies := make(chan string, 2)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body) //nolint: errcheck
+		bodies <- string(body)
+
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRetry(client.RetryPolicy{
+			Backoff: &retry.ExponentialJitterBackoff{MaxAttempts: 3},
+		}),
+	)
+
+	request := client.Put("endpoint").WithPayload("text/plain", io.MultiReader(strings.NewReader("measurement data")))
+
+	_, err := c.Do(context.Background(), request)
+	require.ErrorIs(t, err, client.ErrServiceUnavailable)
+	require.Equal(t, int32(1), attempts.Load())
+	require.Equal(t, "measurement data", <-bodies)
+
+	_, err = c.Do(context.Background(), request)
+	require.ErrorIs(t, err, client.ErrPayloadConsumed)
+	require.Equal(t, int32(1), attempts.Load())
+}
@@ -4,7 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/go-http-utils/headers"
 
 	"github.com/SKF/go-rest-utility/problems"
 )
@@ -13,6 +19,17 @@ type ProblemDecoder interface {
 	DecodeProblem(context.Context, *http.Response) (problems.Problem, error)
 }
 
+// problemMediaType returns the media type of the response, ignoring any
+// parameters, and whether it is one of the problem media types.
+func problemMediaType(header http.Header) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(header.Get(headers.ContentType))
+	if err != nil {
+		return "", false
+	}
+
+	return mediaType, mediaType == problems.ContentType || mediaType == problems.XMLContentType
+}
+
 type BasicProblemDecoder struct{}
 
 func (d *BasicProblemDecoder) DecodeProblem(_ context.Context, resp *http.Response) (problems.Problem, error) {
@@ -25,3 +42,178 @@ func (d *BasicProblemDecoder) DecodeProblem(_ context.Context, resp *http.Respon
 
 	return problem, nil
 }
+
+// UnknownProblem is a problem whose type is not registered in the
+// ProblemRegistry. Members not part of problems.BasicProblem are kept in
+// Extensions.
+type UnknownProblem struct {
+	problems.BasicProblem
+	Extensions map[string]json.RawMessage
+}
+
+// Unwrap makes the underlying BasicProblem available to errors.As.
+func (p UnknownProblem) Unwrap() error {
+	return p.BasicProblem
+}
+
+// Is makes UnknownProblem match the HTTPError with the same status code, such
+// that errors.Is(err, ErrNotFound) holds for problems as well.
+func (p UnknownProblem) Is(target error) bool {
+	httpErr, ok := target.(HTTPError)
+	return ok && httpErr.StatusCode == p.Status
+}
+
+// RegisteredProblem is a problem decoded into the Go type registered in the
+// ProblemRegistry. It makes errors.Is(err, ErrBadRequest) hold by status code,
+// while errors.As finds the registered type.
+type RegisteredProblem struct {
+	problems.Problem
+	status int
+}
+
+func newRegisteredProblem(problem problems.Problem, status int) RegisteredProblem {
+	if status == 0 {
+		status = problem.ProblemStatus()
+	}
+
+	return RegisteredProblem{Problem: problem, status: status}
+}
+
+// Unwrap makes the registered problem available to errors.As.
+func (p RegisteredProblem) Unwrap() error {
+	return p.Problem
+}
+
+// Is makes RegisteredProblem match the HTTPError with the same status code.
+func (p RegisteredProblem) Is(target error) bool {
+	httpErr, ok := target.(HTTPError)
+	return ok && httpErr.StatusCode == p.status
+}
+
+var basicProblemMembers = []string{"type", "title", "status", "detail", "instance", "correlationId"}
+
+// ProblemRegistry is a ProblemDecoder which decodes problems into the Go type
+// registered for their problem type URI, such that errors.As can be used to
+// access the members of the specific problem. Both the JSON and the XML
+// format of problems are supported.
+type ProblemRegistry struct {
+	mu    sync.RWMutex
+	types map[string]reflect.Type
+}
+
+// Ensure that ProblemRegistry implements ProblemDecoder.
+var _ ProblemDecoder = &ProblemRegistry{}
+
+// NewProblemRegistry returns a ProblemRegistry with the problem types of the
+// problems package already registered.
+func NewProblemRegistry() *ProblemRegistry {
+	registry := &ProblemRegistry{
+		types: make(map[string]reflect.Type),
+	}
+
+	registry.Register(problems.Validation())
+
+	return registry
+}
+
+// Register associates the problem type URI of the prototype with its Go type.
+// The prototype may be either a value or a pointer.
+func (r *ProblemRegistry) Register(prototype problems.Problem) *ProblemRegistry {
+	return r.RegisterType(prototype.ProblemType(), prototype)
+}
+
+// RegisterType associates the problem type URI with the Go type of the prototype.
+func (r *ProblemRegistry) RegisterType(problemType string, prototype problems.Problem) *ProblemRegistry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.types == nil {
+		r.types = make(map[string]reflect.Type)
+	}
+
+	r.types[problemType] = reflect.TypeOf(prototype)
+
+	return r
+}
+
+func (r *ProblemRegistry) lookup(problemType string) (reflect.Type, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, found := r.types[problemType]
+
+	return t, found
+}
+
+func (r *ProblemRegistry) DecodeProblem(_ context.Context, resp *http.Response) (problems.Problem, error) {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read problem: %w", err)
+	}
+
+	if mediaType, _ := problemMediaType(resp.Header); mediaType == problems.XMLContentType {
+		if body, err = problemXMLToJSON(body); err != nil {
+			return nil, err
+		}
+	}
+
+	var header struct {
+		Type string `json:"type"`
+	}
+
+	if err := json.Unmarshal(body, &header); err != nil {
+		return nil, fmt.Errorf("ProblemRegistry json decoder: %w", err)
+	}
+
+	if t, found := r.lookup(header.Type); found {
+		problem, err := decodeRegisteredProblem(t, body)
+		if err != nil {
+			return nil, err
+		}
+
+		return newRegisteredProblem(problem, resp.StatusCode), nil
+	}
+
+	return decodeUnknownProblem(body)
+}
+
+func decodeRegisteredProblem(t reflect.Type, body []byte) (problems.Problem, error) {
+	isPointer := t.Kind() == reflect.Pointer
+	if isPointer {
+		t = t.Elem()
+	}
+
+	value := reflect.New(t)
+
+	if err := json.Unmarshal(body, value.Interface()); err != nil {
+		return nil, fmt.Errorf("ProblemRegistry json decoder: %s: %w", t, err)
+	}
+
+	if !isPointer {
+		value = value.Elem()
+	}
+
+	problem, _ := value.Interface().(problems.Problem) //nolint: errcheck
+
+	return problem, nil
+}
+
+func decodeUnknownProblem(body []byte) (problems.Problem, error) {
+	problem := UnknownProblem{}
+
+	if err := json.Unmarshal(body, &problem.BasicProblem); err != nil {
+		return nil, fmt.Errorf("ProblemRegistry json decoder: %w", err)
+	}
+
+	if err := json.Unmarshal(body, &problem.Extensions); err != nil {
+		return nil, fmt.Errorf("ProblemRegistry json decoder: %w", err)
+	}
+
+	for _, member := range basicProblemMembers {
+		delete(problem.Extensions, member)
+	}
+
+	return problem, nil
+}
@@ -4,23 +4,102 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/SKF/go-rest-utility/client"
 	"github.com/SKF/go-rest-utility/problems"
+	mux_problems "github.com/SKF/go-rest-utility/server/gorillamux/problems"
 )
 
-// The default client should not consider Problems for backward compatibility reasons
-// should probably be included in the default client in the next major version (v1).
 func TestClientGetWithoutProblemDecoder(t *testing.T) {
+	_, err := setupProblemServer(errors.New("internal error"), client.WithoutProblemDecoder())
+
+	require.Error(t, err)
+	require.ErrorIs(t, err, client.ErrInternalServerError)
+	require.IsType(t, client.HTTPError{}, err)
+}
+
+func TestClientGetWithDefaultProblemDecoder(t *testing.T) {
 	_, err := setupProblemServer(errors.New("internal error"))
 
 	require.Error(t, err)
 	require.ErrorIs(t, err, client.ErrInternalServerError)
+	require.Implements(t, (*problems.Problem)(nil), err)
+
+	actualProblem := err.(problems.Problem)
+	require.Equal(t, "/problems/internal-server-error", actualProblem.ProblemType())
+}
+
+func TestClientGetWithProblemContentTypeParameters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", problems.ContentType+"; charset=utf-8")
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"type": "/problems/conflict", "title": "Conflict", "status": 409}`)) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	_, err := client.NewClient(client.WithBaseURL(srv.URL)).Do(context.Background(), client.Get("endpoint"))
+	require.ErrorIs(t, err, client.ErrConflict)
+
+	var basicProblem problems.BasicProblem
+
+	require.ErrorAs(t, err, &basicProblem)
+	require.Equal(t, "/problems/conflict", basicProblem.Type)
+}
+
+func TestClientGetWithXMLProblem(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", problems.XMLContentType)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<problem xmlns="urn:ietf:rfc:7807">
+  <type>/problems/invalid-request</type>
+  <title>Your request parameters didn't validate.</title>
+  <status>400</status>
+  <reasons>
+    <i><name>label</name><reason>must not be empty</reason></i>
+  </reasons>
+</problem>`)) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	_, err := client.NewClient(client.WithBaseURL(srv.URL)).Do(context.Background(), client.Get("endpoint"))
+	require.Error(t, err)
+
+	var validationProblem problems.ValidationProblem
+
+	require.ErrorAs(t, err, &validationProblem)
+	require.Equal(t, http.StatusBadRequest, validationProblem.Status)
+	require.Equal(t, []problems.ValidationReason{{Name: "label", Reason: "must not be empty"}}, validationProblem.Reasons)
+}
+
+func TestXMLProblemDecoder(t *testing.T) {
+	resp := &http.Response{
+		Header: http.Header{"Content-Type": []string{problems.XMLContentType}},
+		Body: io.NopCloser(strings.NewReader(`<problem xmlns="urn:ietf:rfc:7807">
+  <type>https://example.com/probs/out-of-credit</type>
+  <title>You do not have enough credit.</title>
+  <detail>Your current balance is 30, but that costs 50.</detail>
+  <instance>https://example.net/account/12345/msgs/abc</instance>
+  <balance>30</balance>
+</problem>`)),
+	}
+
+	problem, err := new(client.XMLProblemDecoder).DecodeProblem(context.Background(), resp)
+	require.NoError(t, err)
+
+	require.Equal(t, problems.BasicProblem{
+		Type:     "https://example.com/probs/out-of-credit",
+		Title:    "You do not have enough credit.",
+		Detail:   "Your current balance is 30, but that costs 50.",
+		Instance: "https://example.net/account/12345/msgs/abc",
+	}, problem)
 }
 
 func TestClientGetWithBasicProblemDecoder(t *testing.T) {
@@ -102,3 +181,95 @@ type ProblemDecoderFn func(context.Context, *http.Response) (problems.Problem, e
 func (fn ProblemDecoderFn) DecodeProblem(ctx context.Context, resp *http.Response) (problems.Problem, error) {
 	return fn(ctx, resp)
 }
+
+func TestClientGetWithProblemRegistry_Validation(t *testing.T) {
+	expectedProblem := problems.Validation(problems.ValidationReason{
+		Name:   "label",
+		Reason: "must not be empty",
+	})
+
+	_, err := setupProblemServer(expectedProblem, client.WithProblemDecoder(client.NewProblemRegistry()))
+	require.Error(t, err)
+
+	var validationProblem problems.ValidationProblem
+
+	require.ErrorAs(t, err, &validationProblem)
+	require.Equal(t, expectedProblem.Type, validationProblem.Type)
+	require.Equal(t, []problems.ValidationReason{{Name: "label", Reason: "must not be empty"}}, validationProblem.Reasons)
+}
+
+func TestClientGetWithProblemRegistry_RegisteredPointer(t *testing.T) {
+	expectedProblem := mux_problems.MethodNotAllowed(http.MethodPatch, http.MethodGet, http.MethodPut)
+
+	registry := client.NewProblemRegistry().
+		Register(&mux_problems.MethodNotAllowedProblem{BasicProblem: problems.BasicProblem{Type: expectedProblem.Type}})
+
+	_, err := setupProblemServer(expectedProblem, client.WithProblemDecoder(registry))
+	require.Error(t, err)
+
+	var methodNotAllowed *mux_problems.MethodNotAllowedProblem
+
+	require.ErrorAs(t, err, &methodNotAllowed)
+	require.Equal(t, http.MethodPatch, methodNotAllowed.Method)
+	require.Equal(t, []string{http.MethodGet, http.MethodPut}, methodNotAllowed.Allowed)
+}
+
+func TestClientGetWithProblemRegistry_Unknown(t *testing.T) {
+	type QuotaProblem struct {
+		problems.BasicProblem
+		Quota int `json:"quota"`
+	}
+
+	expectedProblem := QuotaProblem{
+		BasicProblem: problems.BasicProblem{
+			Type:   "/problems/quota-exceeded",
+			Title:  "Quota exceeded",
+			Status: http.StatusTooManyRequests,
+		},
+		Quota: 100,
+	}
+
+	_, err := setupProblemServer(expectedProblem, client.WithProblemDecoder(client.NewProblemRegistry()))
+	require.Error(t, err)
+
+	var unknownProblem client.UnknownProblem
+
+	require.ErrorAs(t, err, &unknownProblem)
+	require.Equal(t, json.RawMessage("100"), unknownProblem.Extensions["quota"])
+	require.NotContains(t, unknownProblem.Extensions, "title")
+
+	var basicProblem problems.BasicProblem
+
+	require.ErrorAs(t, err, &basicProblem)
+	require.Equal(t, expectedProblem.Type, basicProblem.Type)
+	require.Equal(t, expectedProblem.Status, basicProblem.Status)
+}
+
+func TestClientGetWithProblemRegistry_RegisteredMatchesStatus(t *testing.T) {
+	_, err := setupProblemServer(problems.Validation())
+	require.ErrorIs(t, err, client.ErrBadRequest)
+	require.NotErrorIs(t, err, client.ErrNotFound)
+
+	var validationProblem problems.ValidationProblem
+
+	require.ErrorAs(t, err, &validationProblem)
+	require.Equal(t, "/problems/invalid-request", validationProblem.Type)
+	require.Implements(t, (*problems.Problem)(nil), err)
+}
+
+func TestClientGetWithMalformedProblem(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", problems.ContentType)
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`upstream failed`)) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	_, err := client.NewClient(client.WithBaseURL(srv.URL)).Do(context.Background(), client.Get("endpoint"))
+	require.ErrorIs(t, err, client.ErrBadGateway)
+
+	var httpErr client.HTTPError
+
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, "upstream failed", string(httpErr.RawBody()))
+}
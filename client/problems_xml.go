@@ -0,0 +1,113 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/SKF/go-rest-utility/problems"
+)
+
+// XMLProblemDecoder decodes problems in the XML format of RFC 9457 Appendix B
+// into a problems.BasicProblem.
+type XMLProblemDecoder struct{}
+
+func (d *XMLProblemDecoder) DecodeProblem(_ context.Context, resp *http.Response) (problems.Problem, error) {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read problem: %w", err)
+	}
+
+	converted, err := problemXMLToJSON(body)
+	if err != nil {
+		return nil, err
+	}
+
+	problem := problems.BasicProblem{}
+	if err := json.Unmarshal(converted, &problem); err != nil {
+		return nil, fmt.Errorf("BasicProblem json decoder: %w", err)
+	}
+
+	return problem, nil
+}
+
+// xmlElement is a generic XML element, used to convert XML problems into
+// their JSON equivalent so that they can be decoded into the same Go types.
+type xmlElement struct {
+	XMLName  xml.Name
+	Content  string       `xml:",chardata"`
+	Children []xmlElement `xml:",any"`
+}
+
+// problemXMLToJSON converts a problem in the XML format into its JSON format.
+// Elements only holding `i` elements become arrays, elements holding other
+// elements become objects and all other elements become numbers or booleans
+// if possible and strings otherwise.
+func problemXMLToJSON(body []byte) ([]byte, error) {
+	var root xmlElement
+
+	if err := xml.NewDecoder(bytes.NewReader(body)).Decode(&root); err != nil {
+		return nil, fmt.Errorf("problem xml decoder: %w", err)
+	}
+
+	object := make(map[string]interface{}, len(root.Children))
+
+	for _, child := range root.Children {
+		if slices.Contains(basicProblemMembers, child.XMLName.Local) && child.XMLName.Local != "status" {
+			object[child.XMLName.Local] = strings.TrimSpace(child.Content)
+			continue
+		}
+
+		object[child.XMLName.Local] = child.value()
+	}
+
+	return json.Marshal(object)
+}
+
+func (e xmlElement) value() interface{} {
+	if len(e.Children) == 0 {
+		return scalarValue(strings.TrimSpace(e.Content))
+	}
+
+	isArray := true
+	for _, child := range e.Children {
+		isArray = isArray && child.XMLName.Local == "i"
+	}
+
+	if isArray {
+		array := make([]interface{}, 0, len(e.Children))
+		for _, child := range e.Children {
+			array = append(array, child.value())
+		}
+
+		return array
+	}
+
+	object := make(map[string]interface{}, len(e.Children))
+	for _, child := range e.Children {
+		object[child.XMLName.Local] = child.value()
+	}
+
+	return object
+}
+
+func scalarValue(text string) interface{} {
+	var value interface{}
+
+	if err := json.Unmarshal([]byte(text), &value); err == nil {
+		switch value.(type) {
+		case float64, bool:
+			return json.RawMessage(text)
+		}
+	}
+
+	return text
+}
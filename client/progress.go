@@ -0,0 +1,74 @@
+package client
+
+import (
+	"io"
+	"sync"
+)
+
+// ProgressFunc is called as the body of a request or a download is being
+// transferred, with the number of bytes transferred so far and the total
+// size in bytes, or -1 if unknown.
+type ProgressFunc func(transferred, total int64)
+
+// WithProgress reports the progress of sending the body of the Request, or
+// of Client.Download receiving the resource. The progress of a body starts
+// over whenever the body is sent again, such as on retries and redirects.
+func (r *Request) WithProgress(fn ProgressFunc) *Request {
+	r.progress = fn
+
+	return r
+}
+
+// progressCounter sums up the bytes transferred by one or more concurrent
+// readers, reporting every change to its ProgressFunc.
+type progressCounter struct {
+	mu          sync.Mutex
+	fn          ProgressFunc
+	transferred int64
+	total       int64
+}
+
+func (pc *progressCounter) add(n int64) {
+	if n == 0 {
+		return
+	}
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	pc.transferred += n
+	pc.fn(pc.transferred, pc.total)
+}
+
+type progressReader struct {
+	io.ReadCloser
+	counter *progressCounter
+}
+
+func (pr progressReader) Read(p []byte) (int, error) {
+	n, err := pr.ReadCloser.Read(p)
+	pr.counter.add(int64(n))
+
+	return n, err
+}
+
+// progressPayload reports the progress of reading the body of the payload,
+// starting over every time it is opened.
+type progressPayload struct {
+	payload payload
+	fn      ProgressFunc
+}
+
+func (pp progressPayload) open() (io.ReadCloser, error) {
+	body, err := pp.payload.open()
+	if err != nil {
+		return nil, err
+	}
+
+	counter := &progressCounter{fn: pp.fn, total: pp.payload.contentLength()}
+
+	return progressReader{ReadCloser: body, counter: counter}, nil
+}
+
+func (pp progressPayload) contentLength() int64 { return pp.payload.contentLength() }
+func (pp progressPayload) replayable() bool     { return pp.payload.replayable() }
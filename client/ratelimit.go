@@ -0,0 +1,147 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	rateLimitRemainingHeader = "RateLimit-Remaining"
+	rateLimitResetHeader     = "RateLimit-Reset"
+)
+
+// RateLimit configures the client-side rate limit of requests to each host.
+//
+// The rate adapts to the `RateLimit-Remaining` and `RateLimit-Reset` headers
+// of the responses, but never exceeds the configured rate, and all requests
+// are held back for the duration of the `Retry-After` header of a 429
+// response.
+type RateLimit struct {
+	// Rate is the number of requests per second allowed to each host. Zero
+	// means no fixed limit, only the one reported by the server.
+	Rate float64
+	// Burst is the number of requests which may be sent at once, defaults to 1.
+	Burst int
+}
+
+// RateLimitMiddleware blocks every request until the rate limit of its host
+// allows it to be sent or the context of the request is done.
+func RateLimitMiddleware(limit RateLimit) Middleware {
+	if limit.Burst <= 0 {
+		limit.Burst = 1
+	}
+
+	if limit.Rate <= 0 {
+		limit.Rate = float64(rate.Inf)
+	}
+
+	limiters := &hostLimiters{
+		limit: limit,
+		hosts: make(map[string]*hostLimiter),
+	}
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			limiter := limiters.get(req.URL.Host)
+
+			if err := limiter.wait(req); err != nil {
+				closeRequestBody(req)
+				return nil, fmt.Errorf("waiting for rate limit: %w", err)
+			}
+
+			resp, err := next.RoundTrip(req)
+			if err == nil {
+				limiter.adapt(resp, time.Now())
+			}
+
+			return resp, err
+		})
+	}
+}
+
+type hostLimiters struct {
+	limit RateLimit
+
+	mu    sync.Mutex
+	hosts map[string]*hostLimiter
+}
+
+func (l *hostLimiters) get(host string) *hostLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, exists := l.hosts[host]
+	if !exists {
+		limiter = &hostLimiter{
+			rate:    rate.Limit(l.limit.Rate),
+			limiter: rate.NewLimiter(rate.Limit(l.limit.Rate), l.limit.Burst),
+		}
+
+		l.hosts[host] = limiter
+	}
+
+	return limiter
+}
+
+type hostLimiter struct {
+	rate    rate.Limit
+	limiter *rate.Limiter
+
+	mu           sync.Mutex
+	blockedUntil time.Time
+}
+
+func (l *hostLimiter) wait(req *http.Request) error {
+	l.mu.Lock()
+	blockedFor := time.Until(l.blockedUntil)
+	l.mu.Unlock()
+
+	if blockedFor > 0 {
+		if err := sleepContext(req.Context(), blockedFor); err != nil {
+			return err
+		}
+	}
+
+	return l.limiter.Wait(req.Context())
+}
+
+// adapt lowers the rate to what the server reports as remaining, or blocks
+// all requests until the server is ready to accept them again.
+func (l *hostLimiter) adapt(resp *http.Response, now time.Time) {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if retryAfter, ok := parseRetryAfter(resp.Header, now); ok {
+			l.blockUntil(now.Add(retryAfter))
+		}
+	}
+
+	remaining, err := strconv.ParseInt(resp.Header.Get(rateLimitRemainingHeader), 10, 64)
+	if err != nil || remaining < 0 {
+		return
+	}
+
+	reset, err := strconv.ParseInt(resp.Header.Get(rateLimitResetHeader), 10, 64)
+	if err != nil || reset <= 0 {
+		return
+	}
+
+	if remaining == 0 {
+		l.blockUntil(now.Add(time.Duration(reset) * time.Second))
+		return
+	}
+
+	l.limiter.SetLimitAt(now, min(l.rate, rate.Limit(float64(remaining)/float64(reset))))
+}
+
+func (l *hostLimiter) blockUntil(until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until.After(l.blockedUntil) {
+		l.blockedUntil = until
+	}
+}
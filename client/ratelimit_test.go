@@ -0,0 +1,88 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client"
+)
+
+func TestClientRateLimit(t *testing.T) {
+	srv := newEchoHTTPServer()
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRateLimit(client.RateLimit{Rate: 20}),
+	)
+
+	start := time.Now()
+
+	for range 3 {
+		response, err := c.Do(context.Background(), client.Get("endpoint"))
+		require.NoError(t, err)
+		require.NoError(t, response.Close())
+	}
+
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestClientRateLimit_RetryAfterBlocksUntilContextDone(t *testing.T) {
+	requests := new(atomic.Int32)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set(headers.RetryAfter, "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRateLimit(client.RateLimit{Rate: 100, Burst: 10}),
+	)
+
+	_, err := c.Do(context.Background(), client.Get("endpoint"))
+	require.ErrorIs(t, err, client.ErrTooManyRequests)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = c.Do(ctx, client.Get("endpoint"))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, int32(1), requests.Load())
+}
+
+func TestClientRateLimit_RemainingZeroBlocks(t *testing.T) {
+	requests := new(atomic.Int32)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("RateLimit-Remaining", "0")
+		w.Header().Set("RateLimit-Reset", "30")
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRateLimit(client.RateLimit{Rate: 100, Burst: 10}),
+	)
+
+	response, err := c.Do(context.Background(), client.Get("endpoint"))
+	require.NoError(t, err)
+	require.NoError(t, response.Close())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = c.Do(ctx, client.Get("endpoint"))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, int32(1), requests.Load())
+}
@@ -5,10 +5,6 @@ import (
 	"net/http"
 )
 
-type key int
-
-var followRedirectsKey key
-
 func redirectHandler(req *http.Request, via []*http.Request) error {
 	// Default behavior from net/http
 	if len(via) >= 10 { //nolint: mnd
@@ -48,7 +48,7 @@ func TestClientRedirects_Follows(t *testing.T) {
 	require.Equal(t, "/endpoint", echo.URL)
 	require.Equal(t, http.MethodGet, echo.Method)
 	require.Equal(t, DefaultUserAgent, echo.Header.Get(headers.UserAgent))
-	require.Equal(t, DefaultAcceptEncoding, echo.Header.Get(headers.AcceptEncoding))
+	require.Equal(t, AcceptEncoding(), echo.Header.Get(headers.AcceptEncoding))
 }
 
 func TestClientRedirects_Default(t *testing.T) {
@@ -71,5 +71,5 @@ func TestClientRedirects_Default(t *testing.T) {
 	require.Equal(t, "/endpoint", echo.URL)
 	require.Equal(t, http.MethodGet, echo.Method)
 	require.Equal(t, DefaultUserAgent, echo.Header.Get(headers.UserAgent))
-	require.Equal(t, DefaultAcceptEncoding, echo.Header.Get(headers.AcceptEncoding))
+	require.Equal(t, AcceptEncoding(), echo.Header.Get(headers.AcceptEncoding))
 }
@@ -1,10 +1,9 @@
 package client
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -14,13 +13,22 @@ import (
 )
 
 type Request struct {
-	uriTemplate  string
-	uriVariables map[string]interface{}
+	uriTemplate       string
+	uriVariables      map[string]interface{}
+	explodedVariables map[string]bool
+	assignErr         error
+
+	// url is sent instead of the expanded URI template, which is then only
+	// used to name the Request, such as for links to follow.
+	url *url.URL
 
 	method          string
 	header          http.Header
-	body            io.Reader
+	body            payload
 	followRedirects bool
+	retry           *bool
+	compression     *RequestCompression
+	progress        ProgressFunc
 }
 
 func NewRequest(method, uriTemplate string) *Request {
@@ -30,7 +38,7 @@ func NewRequest(method, uriTemplate string) *Request {
 
 		method:          method,
 		header:          make(http.Header),
-		body:            http.NoBody,
+		body:            noPayload{},
 		followRedirects: true,
 	}
 }
@@ -104,36 +112,50 @@ func (r *Request) Assign(variable string, value interface{}) *Request {
 	return r
 }
 
+// Clone returns a copy of the Request which can be modified without
+// affecting the original.
+//
+// The Client never modifies a Request, so a Request can be sent concurrently
+// and prepared once to be used as a template, as long as it is only modified
+// through clones. Payloads are shared between clones, so a payload set with
+// WithPayload from a reader, or a File, can not be sent concurrently.
+//
+//	var getNode = client.Get("nodes/{id}").SetHeader(headers.Accept, "application/json")
+//
+//	response, err := c.Do(ctx, getNode.Clone().Assign("id", id))
+func (r *Request) Clone() *Request {
+	clone := *r
+	clone.uriVariables = maps.Clone(r.uriVariables)
+	clone.explodedVariables = maps.Clone(r.explodedVariables)
+	clone.header = r.header.Clone()
+
+	if body, ok := r.body.(interface{ clone() payload }); ok {
+		clone.body = body.clone()
+	}
+
+	return &clone
+}
+
 func (r *Request) SetHeader(key, value string) *Request {
 	r.header.Set(key, value)
 
 	return r
 }
 
-type jsonPayload struct {
-	payload interface{}
-	buffer  io.Reader
-}
-
-func (jp *jsonPayload) Read(p []byte) (n int, err error) {
-	if jp.buffer == nil {
-		switch payload := jp.payload.(type) {
-		case []byte:
-			jp.buffer = bytes.NewBuffer(payload)
-		case string:
-			jp.buffer = bytes.NewBufferString(payload)
-		default:
-			buf := new(bytes.Buffer)
+// IfMatch makes the Request conditional on the resource still having the
+// entity tag, otherwise the server responds with ErrPreconditionFailed.
+func (r *Request) IfMatch(etag string) *Request {
+	r.header.Set(headers.IfMatch, etag)
 
-			if err = json.NewEncoder(buf).Encode(payload); err != nil {
-				return
-			}
+	return r
+}
 
-			jp.buffer = buf
-		}
-	}
+// IfNoneMatch makes the Request conditional on the resource not having the
+// entity tag, or not existing at all if etag is "*".
+func (r *Request) IfNoneMatch(etag string) *Request {
+	r.header.Set(headers.IfNoneMatch, etag)
 
-	return jp.buffer.Read(p)
+	return r
 }
 
 func (r *Request) WithJSONPayload(payload interface{}) *Request {
@@ -143,9 +165,54 @@ func (r *Request) WithJSONPayload(payload interface{}) *Request {
 	return r
 }
 
+// WithPayloadAs sets the body of the Request to the payload encoded with the
+// codec of the media type, as registered with the Client sending it.
+//
+//	client.Post("nodes").WithPayloadAs("application/yaml", node)
+func (r *Request) WithPayloadAs(mediaType string, payload interface{}) *Request {
+	r.header.Set(headers.ContentType, mediaType)
+	r.body = codecPayload{mediaType: mediaType, payload: payload}
+
+	return r
+}
+
+// WithFormPayload sets the body of the Request to the URL encoded form values.
+func (r *Request) WithFormPayload(values url.Values) *Request {
+	r.header.Set(headers.ContentType, "application/x-www-form-urlencoded")
+	r.body = bytesPayload(values.Encode())
+
+	return r
+}
+
+// WithPayload sets the body of the Request. If the payload also implements
+// io.Seeker it will be rewound and sent again on retries and redirects, as
+// will a snapshot of buffers such as *bytes.Buffer, otherwise it can only be
+// sent once.
 func (r *Request) WithPayload(contentType string, payload io.Reader) *Request {
 	r.header.Set(headers.ContentType, contentType)
-	r.body = payload
+	r.body = &readerPayload{reader: payload}
+
+	// Buffers are sent as a snapshot of their contents, which, just as
+	// seekers, can be resent.
+	if buffer, ok := payload.(interface{ Bytes() []byte }); ok {
+		r.body = bytesPayload(buffer.Bytes())
+		return r
+	}
+
+	if seeker, ok := payload.(io.ReadSeeker); ok {
+		if body, err := newSeekerPayload(seeker); err == nil {
+			r.body = body
+		}
+	}
+
+	return r
+}
+
+// WithPayloadFunc sets the body of the Request to the reader returned by fn,
+// which is called again whenever the body needs to be resent.
+func (r *Request) WithPayloadFunc(contentType string, fn BodyFunc) *Request {
+	r.header.Set(headers.ContentType, contentType)
+	r.body = funcPayload(fn)
 
 	return r
 }
@@ -156,11 +223,31 @@ func (r *Request) WithFollowRedirects(follow bool) *Request {
 	return r
 }
 
+// WithRetry overrides whether the RetryPolicy of the Client applies to this
+// Request. By default only idempotent requests are retried.
+func (r *Request) WithRetry(retry bool) *Request {
+	r.retry = &retry
+
+	return r
+}
+
 // ExpandURL combines the baseURL with the expanded URI template to form the
 // final URL to be used for this Request.
 // If no baseURL is provided the returned URL is just the expanded URI template
 func (r *Request) ExpandURL(baseURL *url.URL) (*url.URL, error) {
-	template, err := uritemplates.Parse(r.uriTemplate)
+	if r.assignErr != nil {
+		return nil, fmt.Errorf("unable to assign uri variables: %w", r.assignErr)
+	}
+
+	if r.url != nil {
+		if baseURL == nil {
+			return r.url, nil
+		}
+
+		return baseURL.ResolveReference(r.url), nil
+	}
+
+	template, err := uritemplates.Parse(explodeTemplate(r.uriTemplate, r.explodedVariables))
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse uri template: %w", err)
 	}
@@ -1,8 +1,13 @@
 package client
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -102,6 +107,59 @@ func TestExpandURLWithPathAndTrailingSlash(t *testing.T) {
 	require.Equal(t, "https://example.com/path/endpoint", url.String())
 }
 
+func TestRequestClone(t *testing.T) {
+	original := Post("nodes/{id}").
+		Assign("id", 1).
+		SetHeader("X-Tenant", "skf").
+		Field("description", "original")
+
+	clone := original.Clone().
+		Assign("id", 2).
+		SetHeader("X-Tenant", "other").
+		Field("label", "clone")
+
+	require.Equal(t, 1, original.uriVariables["id"])
+	require.Equal(t, "skf", original.header.Get("X-Tenant"))
+	require.Len(t, original.body.(*multipartPayload).parts, 1)
+
+	require.Equal(t, 2, clone.uriVariables["id"])
+	require.Equal(t, "other", clone.header.Get("X-Tenant"))
+	require.Len(t, clone.body.(*multipartPayload).parts, 2)
+}
+
+func TestClientDoesNotModifyRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	client := NewClient(
+		WithBaseURL(srv.URL),
+		WithDefaultHeader("X-Client-ID", "78147f11-62d9-4af0-917d-a0eb26d1c1fc"),
+		WithRequestCompression(RequestCompression{Encoding: "gzip"}),
+	)
+
+	request := Put("nodes/{id}").Assign("id", 1).WithJSONPayload(map[string]string{"label": "pump"})
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			response, err := client.Do(context.Background(), request)
+			require.NoError(t, err)
+			require.NoError(t, response.Close())
+		}()
+	}
+
+	wg.Wait()
+
+	require.Equal(t, http.Header{"Content-Type": {"application/json"}}, request.header)
+}
+
 func urlMustParse(rawurl string) *url.URL {
 	parsedURL, err := url.Parse(rawurl)
 	if err != nil {
@@ -110,3 +168,123 @@ func urlMustParse(rawurl string) *url.URL {
 
 	return parsedURL
 }
+
+type nodeState int
+
+func (s nodeState) MarshalText() ([]byte, error) {
+	return []byte([]string{"active", "retired"}[s]), nil
+}
+
+type pagination struct {
+	Limit int     `uri:"limit"`
+	Token *string `uri:"continuationToken"`
+}
+
+type nodeFilter struct {
+	pagination
+	Type    *string    `uri:"type"`
+	State   *nodeState `uri:"state"`
+	Tags    []string   `uri:"tag,explode"`
+	IDs     []int      `uri:"ids"`
+	After   time.Time  `uri:"createdAfter"`
+	Day     *time.Time `uri:"day,date"`
+	Since   time.Time  `uri:"since,unix"`
+	Label   string
+	Ignored string `uri:"-"`
+}
+
+func TestAssignStruct(t *testing.T) {
+	nodeType, state := "sensor", nodeState(1)
+	day := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	request := Get("nodes{?type,state,tag,ids,createdAfter,day,since,Label,Ignored,limit,continuationToken}").
+		AssignStruct(&nodeFilter{
+			pagination: pagination{Limit: 10},
+			Type:       &nodeType,
+			State:      &state,
+			Tags:       []string{"pump", "motor"},
+			IDs:        []int{1, 2},
+			After:      time.Date(2024, 3, 1, 12, 30, 0, 0, time.FixedZone("CET", 3600)),
+			Day:        &day,
+			Since:      time.Unix(1700000000, 0),
+			Label:      "north",
+			Ignored:    "ignored",
+		})
+
+	url, err := request.ExpandURL(nil)
+
+	require.NoError(t, err)
+	require.Equal(t, "nodes?type=sensor&state=retired&tag=pump&tag=motor&ids=1,2"+
+		"&createdAfter=2024-03-01T12%3A30%3A00%2B01%3A00&day=2024-03-01&since=1700000000&Label=north&limit=10",
+		url.String())
+}
+
+func TestAssignStructWithNilPointers(t *testing.T) {
+	request := Get("nodes{?type,state,day,limit}").AssignStruct(nodeFilter{})
+
+	url, err := request.ExpandURL(nil)
+
+	require.NoError(t, err)
+	require.Equal(t, "nodes?limit=0", url.String())
+
+	request = Get("nodes{?type}").AssignStruct((*nodeFilter)(nil))
+
+	url, err = request.ExpandURL(nil)
+
+	require.NoError(t, err)
+	require.Equal(t, "nodes", url.String())
+}
+
+func TestAssignStructWithNestedStruct(t *testing.T) {
+	type query struct {
+		Filter struct {
+			Type  string `uri:"type"`
+			Label *string
+		} `uri:"filter,explode"`
+		Fields map[string]int `uri:"fields"`
+	}
+
+	var q query
+	q.Filter.Type = "sensor"
+	q.Fields = map[string]int{"depth": 2}
+
+	request := Get("nodes{?filter,fields}").AssignStruct(q)
+
+	url, err := request.ExpandURL(nil)
+
+	require.NoError(t, err)
+	require.Equal(t, "nodes?type=sensor&fields=depth,2", url.String())
+}
+
+func TestAssignStructExplodeIsCloned(t *testing.T) {
+	original := Get("nodes{?tag}").Assign("tag", []string{"a", "b"})
+	clone := original.Clone().AssignStruct(struct {
+		Tags []string `uri:"tag,explode"`
+	}{[]string{"a", "b"}})
+
+	url, err := original.ExpandURL(nil)
+	require.NoError(t, err)
+	require.Equal(t, "nodes?tag=a,b", url.String())
+
+	url, err = clone.ExpandURL(nil)
+	require.NoError(t, err)
+	require.Equal(t, "nodes?tag=a&tag=b", url.String())
+}
+
+func TestAssignStructWithInvalidValue(t *testing.T) {
+	_, err := Get("nodes{?limit}").AssignStruct(10).ExpandURL(nil)
+	require.ErrorContains(t, err, "expected a struct, got int")
+
+	_, err = Get("nodes{?ids}").AssignStruct(struct {
+		IDs map[int]string `uri:"ids"`
+	}{}).ExpandURL(nil)
+	require.ErrorContains(t, err, "unsupported map key type int")
+}
+
+func TestExplodeTemplate(t *testing.T) {
+	exploded := map[string]bool{"tag": true, "path": true}
+
+	require.Equal(t, "nodes{/path*}{?type,tag*}{&tags,tag*}{?tag:3}",
+		explodeTemplate("nodes{/path}{?type,tag}{&tags,tag*}{?tag:3}", exploded))
+	require.Equal(t, "nodes{?tag", explodeTemplate("nodes{?tag", exploded))
+}
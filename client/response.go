@@ -2,28 +2,48 @@ package client
 
 import (
 	"compress/gzip"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 
 	"github.com/go-http-utils/headers"
+
+	"github.com/SKF/go-rest-utility/client/codec"
 )
 
 type Response struct {
 	http.Response
 }
 
+// Unmarshal decodes the body with the codec of its Content-Type, as
+// registered with the Client, falling back to JSON for unknown media types.
 func (r *Response) Unmarshal(v interface{}) error {
 	defer r.Close()
 
-	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
-		return fmt.Errorf("failed to json decode read bytes: %w", err)
+	codecs := defaultCodecs
+	if r.Request != nil {
+		if registry, ok := r.Request.Context().Value(codecsKey).(*codec.Registry); ok {
+			codecs = registry
+		}
+	}
+
+	decoder, found := codecs.Lookup(r.Header.Get(headers.ContentType))
+	if !found {
+		decoder = codec.JSON{}
+	}
+
+	if err := decoder.Decode(r.Body, v); err != nil {
+		return fmt.Errorf("failed to decode read bytes: %w", err)
 	}
 
 	return nil
 }
 
+// ETag returns the entity tag of the returned representation, if any.
+func (r *Response) ETag() string {
+	return r.Header.Get(headers.ETag)
+}
+
 // Close reads all of the body stream and closes it to make sure that tcp connections can be reused properly
 func (r *Response) Close() error {
 	if _, err := io.Copy(io.Discard, r.Body); err != nil {
@@ -57,25 +77,60 @@ func (r *GzipReader) Close() error {
 // http.Body and a set of headers that matches the decompressed result.
 // If the content-length header is 0, return the body and the header
 // without decompressing.
+//
+// Stacked content codings, such as `Content-Encoding: gzip, br`, are decoded
+// in reverse order. If any of the codings is not registered with
+// RegisterContentEncoding the body is returned as is. Reading more than
+// DefaultMaxDecompressedSize bytes from the body fails with a
+// DecompressedSizeError.
 func DecompressResponse(resp http.Response) (io.ReadCloser, http.Header, error) {
+	return decompressResponse(resp, DefaultMaxDecompressedSize)
+}
+
+// decompressResponse is DecompressResponse with a custom limit of the
+// decompressed size, where a non-positive maxSize disables the limit.
+func decompressResponse(resp http.Response, maxSize int64) (io.ReadCloser, http.Header, error) {
 	if contentLengthHeader := resp.Header.Get(headers.ContentLength); contentLengthHeader == "0" {
 		return resp.Body, resp.Header, nil
 	}
 
-	switch resp.Header.Get(headers.ContentEncoding) {
-	case "gzip":
-		gzipReader, err := gzip.NewReader(resp.Body)
+	codings := contentCodings(resp.Header.Values(headers.ContentEncoding))
+	if len(codings) == 0 {
+		return resp.Body, resp.Header, nil
+	}
+
+	decoders := make([]ContentDecoder, len(codings))
+
+	for i, coding := range codings {
+		decoder, found := lookupContentDecoder(coding)
+		if !found {
+			return resp.Body, resp.Header, nil
+		}
+
+		decoders[len(codings)-1-i] = decoder
+	}
+
+	body := &decodedBody{Reader: resp.Body, body: resp.Body}
+
+	for _, decoder := range decoders {
+		reader, err := decoder(body.Reader)
 		if err != nil {
+			body.body = io.NopCloser(nil)
+			body.Close() //nolint: errcheck
+
 			return resp.Body, nil, err
 		}
 
-		resp.Header.Del(headers.ContentEncoding)
+		body.Reader = reader
+		body.decoders = append(body.decoders, reader)
+	}
 
-		return &GzipReader{
-			Reader: gzipReader,
-			inner:  resp.Body,
-		}, resp.Header, nil
-	default:
-		return resp.Body, resp.Header, nil
+	if maxSize > 0 {
+		body.Reader = &limitedReader{reader: body.Reader, limit: maxSize, remaining: maxSize}
 	}
+
+	resp.Header.Del(headers.ContentEncoding)
+	resp.Header.Del(headers.ContentLength)
+
+	return body, resp.Header, nil
 }
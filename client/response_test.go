@@ -2,13 +2,17 @@ package client
 
 import (
 	"bytes"
+	"compress/flate"
 	"compress/gzip"
+	"compress/zlib"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/andybalholm/brotli"
 	"github.com/go-http-utils/headers"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -117,6 +121,134 @@ func TestDecompressResponseGzipInnerBodyIsClosed(t *testing.T) {
 	require.True(t, verifier.closed)
 }
 
+func TestDecompressResponseEncodings(t *testing.T) {
+	for _, encoding := range []string{"gzip", "deflate", "br", "zstd", "gzip, br", "deflate, zstd, gzip"} {
+		t.Run(encoding, func(t *testing.T) {
+			responseHeader := make(http.Header)
+			responseHeader.Set(headers.ContentEncoding, encoding)
+			responseHeader.Set(headers.ContentLength, "100")
+
+			response := http.Response{ //nolint:bodyclose
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(encodeString(t, `{"foo":"bar"}`, encoding)),
+				Header:     responseHeader,
+			}
+
+			body, header, err := DecompressResponse(response)
+			require.NoError(t, err)
+
+			readBytes, err := io.ReadAll(body)
+			require.NoError(t, err)
+			require.NoError(t, body.Close())
+
+			assert.Equal(t, `{"foo":"bar"}`, string(readBytes))
+			assert.Equal(t, "", header.Get(headers.ContentEncoding))
+			assert.Equal(t, "", header.Get(headers.ContentLength))
+		})
+	}
+}
+
+func TestDecompressResponseRawDeflate(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	w, err := flate.NewWriter(buf, flate.DefaultCompression)
+	require.NoError(t, err)
+
+	_, err = io.WriteString(w, `{"foo":"bar"}`)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	responseHeader := make(http.Header)
+	responseHeader.Set(headers.ContentEncoding, "deflate")
+
+	body, _, err := DecompressResponse(http.Response{Body: io.NopCloser(buf), Header: responseHeader})
+	require.NoError(t, err)
+
+	readBytes, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"foo":"bar"}`, string(readBytes))
+}
+
+func TestDecompressResponseUnknownEncoding(t *testing.T) {
+	responseHeader := make(http.Header)
+	responseHeader.Set(headers.ContentEncoding, "gzip, compress")
+
+	response := http.Response{ //nolint:bodyclose
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader("compressed")),
+		Header:     responseHeader,
+	}
+
+	body, header, err := DecompressResponse(response)
+	require.NoError(t, err)
+
+	readBytes, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "compressed", string(readBytes))
+	assert.Equal(t, "gzip, compress", header.Get(headers.ContentEncoding))
+}
+
+func TestDecompressResponseSizeLimit(t *testing.T) {
+	responseHeader := make(http.Header)
+	responseHeader.Set(headers.ContentEncoding, "zstd")
+
+	response := http.Response{ //nolint:bodyclose
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(encodeString(t, strings.Repeat("0", 1<<20), "zstd")),
+		Header:     responseHeader,
+	}
+
+	body, _, err := decompressResponse(response, 1000)
+	require.NoError(t, err)
+
+	readBytes, err := io.ReadAll(body)
+
+	var sizeErr *DecompressedSizeError
+	require.ErrorAs(t, err, &sizeErr)
+	require.Equal(t, int64(1000), sizeErr.Limit)
+	require.Len(t, readBytes, 1000)
+}
+
+func TestAcceptEncoding(t *testing.T) {
+	require.Equal(t, "gzip, deflate, br, zstd", AcceptEncoding())
+}
+
+// encodeString applies the content codings in the order of the header.
+func encodeString(t *testing.T, data, encoding string) io.Reader {
+	t.Helper()
+
+	encoded := []byte(data)
+
+	for _, coding := range strings.Split(encoding, ",") {
+		buf := new(bytes.Buffer)
+
+		var (
+			w   io.WriteCloser
+			err error
+		)
+
+		switch strings.TrimSpace(coding) {
+		case "gzip":
+			w = gzip.NewWriter(buf)
+		case "deflate":
+			w = zlib.NewWriter(buf)
+		case "br":
+			w = brotli.NewWriter(buf)
+		case "zstd":
+			w, err = zstd.NewWriter(buf)
+			require.NoError(t, err)
+		}
+
+		_, err = w.Write(encoded)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		encoded = buf.Bytes()
+	}
+
+	return bytes.NewReader(encoded)
+}
+
 func gzipString(data string) io.Reader {
 	buf := new(bytes.Buffer)
 
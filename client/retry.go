@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/go-http-utils/headers"
+
+	"github.com/SKF/go-rest-utility/client/retry"
+)
+
+// DefaultRetryStatusCodes are the response status codes retried when a
+// RetryPolicy does not specify any.
+var DefaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy describes when Client.Do should attempt a request again.
+//
+// Only idempotent requests (GET, HEAD, PUT, DELETE and OPTIONS) are retried
+// unless the Request explicitly opts in with Request.WithRetry. Besides the
+// status codes, requests are retried on transient transport errors such as
+// timeouts and connections which were reset or refused.
+type RetryPolicy struct {
+	// Backoff provides the delay before each new attempt. Retrying stops when it
+	// returns retry.ErrBackoffExhausted, if it never does the retries are only
+	// bounded by the context.
+	Backoff retry.BackoffProvider
+
+	// StatusCodes are the response status codes which should be retried,
+	// defaults to DefaultRetryStatusCodes.
+	StatusCodes []int
+}
+
+func (p *RetryPolicy) retryableResponse(resp *http.Response) bool {
+	statusCodes := p.StatusCodes
+	if len(statusCodes) == 0 {
+		statusCodes = DefaultRetryStatusCodes
+	}
+
+	return slices.Contains(statusCodes, resp.StatusCode)
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Client) shouldRetry(r *Request) bool {
+	if c.retryPolicy == nil || c.retryPolicy.Backoff == nil {
+		return false
+	}
+
+	if r.retry != nil {
+		return *r.retry
+	}
+
+	return isIdempotent(r.method)
+}
+
+// doWithRetry performs the request until it succeeds, fails with a
+// non-retryable error or the backoff of the RetryPolicy is exhausted.
+func (c *Client) doWithRetry(ctx context.Context, r *Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		httpRequest, err := c.prepareRequest(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+
+		httpResponse, err := c.sendWithFallback(ctx, r, httpRequest)
+		if ctx.Err() != nil || errors.Is(err, ErrCircuitOpen) {
+			return httpResponse, err
+		}
+
+		if err == nil && !c.retryPolicy.retryableResponse(httpResponse) {
+			return httpResponse, nil
+		}
+
+		if err != nil && !isTransientError(err) {
+			return httpResponse, err
+		}
+
+		// A body which can only be sent once can not be retried.
+		if !r.body.replayable() {
+			return httpResponse, err
+		}
+
+		backoff, backoffErr := c.retryPolicy.Backoff.BackoffByAttempt(attempt)
+		if backoffErr != nil {
+			if errors.Is(backoffErr, retry.ErrBackoffExhausted) {
+				return httpResponse, err
+			}
+
+			discardResponse(httpResponse)
+
+			return nil, fmt.Errorf("failed generating retry backoff: %w", backoffErr)
+		}
+
+		if httpResponse != nil {
+			if retryAfter, ok := parseRetryAfter(httpResponse.Header, time.Now()); ok && retryAfter > backoff {
+				backoff = retryAfter
+			}
+
+			discardResponse(httpResponse)
+		}
+
+		if err := sleepContext(ctx, backoff); err != nil {
+			return nil, fmt.Errorf("waiting to retry request: %w", err)
+		}
+	}
+}
+
+// isTransientError reports whether the request failed in transport in a way
+// which may succeed when sent again, such as a timeout or a connection which
+// was reset or refused. Failures such as TLS verification or getting a token
+// are not transient.
+func isTransientError(err error) bool {
+	var tokenErr tokenError
+	if errors.As(err, &tokenErr) {
+		return false
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// parseRetryAfter parses the Retry-After header, which is either a number of
+// seconds or a HTTP date, into the duration to wait counting from now.
+func parseRetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get(headers.RetryAfter)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+// sleepContext pauses for the duration d or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// discardResponse drains and closes the body so that the connection can be reused.
+func discardResponse(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	io.Copy(io.Discard, resp.Body) //nolint: errcheck
+	resp.Body.Close()              //nolint: errcheck
+}
@@ -35,20 +35,24 @@ type ExponentialJitterBackoff struct {
 	JitterSource io.Reader //
 }
 
+// BackoffByAttempt is safe for concurrent use as long as the JitterSource is,
+// the defaults of unset fields are not written back to the provider.
 func (provider *ExponentialJitterBackoff) BackoffByAttempt(attempts int) (time.Duration, error) {
-	if provider.JitterSource == nil {
-		provider.JitterSource = rand.Reader
+	jitterSource := provider.JitterSource
+	if jitterSource == nil {
+		jitterSource = rand.Reader
 	}
 
-	if provider.Base == 0 {
-		provider.Base = DefaultBackoffBase
+	base := provider.Base
+	if base == 0 {
+		base = DefaultBackoffBase
 	}
 
 	if provider.MaxAttempts > 0 && attempts > provider.MaxAttempts {
 		return 0, ErrBackoffExhausted
 	}
 
-	backoff := int64(provider.Base) * int64(1<<attempts)
+	backoff := int64(base) * int64(1<<attempts)
 	if backoff <= 0 {
 		backoff = MaxBackoff
 	}
@@ -57,7 +61,7 @@ func (provider *ExponentialJitterBackoff) BackoffByAttempt(attempts int) (time.D
 		backoff = Cap
 	}
 
-	jitteredBackoff, err := rand.Int(provider.JitterSource, big.NewInt(backoff))
+	jitteredBackoff, err := rand.Int(jitterSource, big.NewInt(backoff))
 	if err != nil {
 		return 0, err
 	}
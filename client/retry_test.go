@@ -0,0 +1,266 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client"
+	"github.com/SKF/go-rest-utility/client/auth"
+	"github.com/SKF/go-rest-utility/client/retry"
+)
+
+func TestClientRetry_SucceedsAfterRetryableStatus(t *testing.T) {
+	srv, attempts := newFlakyHTTPServer(2, http.StatusServiceUnavailable)
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRetry(client.RetryPolicy{
+			Backoff: &retry.ExponentialJitterBackoff{MaxAttempts: 5},
+		}),
+	)
+
+	response, err := c.Do(context.Background(), client.Get("endpoint"))
+	require.NoError(t, err)
+
+	defer response.Close()
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, int32(3), attempts.Load())
+}
+
+func TestClientRetry_Concurrent(t *testing.T) {
+	srv, attempts := newFlakyHTTPServer(10, http.StatusServiceUnavailable)
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRetry(client.RetryPolicy{
+			Backoff: &retry.ExponentialJitterBackoff{MaxAttempts: 20},
+		}),
+	)
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			response, err := c.Do(context.Background(), client.Get("endpoint"))
+			if assert.NoError(t, err) {
+				response.Close()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	require.Equal(t, int32(20), attempts.Load())
+}
+
+// failingTokenProvider fails to sign in, as with a wrong password.
+type failingTokenProvider struct {
+	calls atomic.Int32
+}
+
+func (p *failingTokenProvider) GetRawToken(context.Context) (auth.RawToken, error) {
+	p.calls.Add(1)
+	return "", errors.New("invalid credentials")
+}
+
+func TestClientRetry_TokenErrorNotRetried(t *testing.T) {
+	srv, attempts := newFlakyHTTPServer(0, http.StatusOK)
+	defer srv.Close()
+
+	provider := new(failingTokenProvider)
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithTokenProvider(provider),
+		client.WithRetry(client.RetryPolicy{
+			Backoff: &retry.ExponentialJitterBackoff{MaxAttempts: 5},
+		}),
+	)
+
+	_, err := c.Do(context.Background(), client.Get("endpoint"))
+	require.ErrorContains(t, err, "invalid credentials")
+	require.Equal(t, int32(1), provider.calls.Load())
+	require.Equal(t, int32(0), attempts.Load())
+}
+
+func TestClientRetry_ConnectionClosed(t *testing.T) {
+	attempts := new(atomic.Int32)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			require.NoError(t, conn.Close())
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRetry(client.RetryPolicy{
+			Backoff: &retry.ExponentialJitterBackoff{MaxAttempts: 5},
+		}),
+	)
+
+	response, err := c.Do(context.Background(), client.Get("endpoint"))
+	require.NoError(t, err)
+	require.NoError(t, response.Close())
+	require.Equal(t, int32(2), attempts.Load())
+}
+
+func TestClientRetry_TLSErrorNotRetried(t *testing.T) {
+	handshakes := new(atomic.Int32)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			handshakes.Add(1)
+		}
+	}
+
+	srv.StartTLS()
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRetry(client.RetryPolicy{
+			Backoff: &retry.ExponentialJitterBackoff{MaxAttempts: 5},
+		}),
+	)
+
+	_, err := c.Do(context.Background(), client.Get("endpoint"))
+	require.Error(t, err)
+	require.Equal(t, int32(1), handshakes.Load())
+}
+
+func TestClientRetry_BackoffExhausted(t *testing.T) {
+	srv, attempts := newFlakyHTTPServer(10, http.StatusBadGateway)
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRetry(client.RetryPolicy{
+			Backoff: &retry.ExponentialJitterBackoff{MaxAttempts: 2},
+		}),
+	)
+
+	_, err := c.Do(context.Background(), client.Get("endpoint"))
+	require.ErrorIs(t, err, client.ErrBadGateway)
+	require.Equal(t, int32(3), attempts.Load())
+}
+
+func TestClientRetry_NonIdempotentNotRetried(t *testing.T) {
+	srv, attempts := newFlakyHTTPServer(1, http.StatusServiceUnavailable)
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRetry(client.RetryPolicy{
+			Backoff: &retry.ExponentialJitterBackoff{MaxAttempts: 5},
+		}),
+	)
+
+	_, err := c.Do(context.Background(), client.Post("endpoint"))
+	require.ErrorIs(t, err, client.ErrServiceUnavailable)
+	require.Equal(t, int32(1), attempts.Load())
+}
+
+func TestClientRetry_RequestOptIn(t *testing.T) {
+	srv, attempts := newFlakyHTTPServer(1, http.StatusServiceUnavailable)
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRetry(client.RetryPolicy{
+			Backoff: &retry.ExponentialJitterBackoff{MaxAttempts: 5},
+		}),
+	)
+
+	response, err := c.Do(context.Background(), client.Post("endpoint").WithRetry(true))
+	require.NoError(t, err)
+
+	defer response.Close()
+
+	require.Equal(t, int32(2), attempts.Load())
+}
+
+func TestClientRetry_RequestOptOut(t *testing.T) {
+	srv, attempts := newFlakyHTTPServer(1, http.StatusServiceUnavailable)
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRetry(client.RetryPolicy{
+			Backoff: &retry.ExponentialJitterBackoff{MaxAttempts: 5},
+		}),
+	)
+
+	_, err := c.Do(context.Background(), client.Get("endpoint").WithRetry(false))
+	require.ErrorIs(t, err, client.ErrServiceUnavailable)
+	require.Equal(t, int32(1), attempts.Load())
+}
+
+func TestClientRetry_RetryAfterRespectsContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(headers.RetryAfter, "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(
+		client.WithBaseURL(srv.URL),
+		client.WithRetry(client.RetryPolicy{
+			Backoff: &retry.ExponentialJitterBackoff{MaxAttempts: 5},
+		}),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.Do(ctx, client.Get("endpoint"))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// newFlakyHTTPServer returns a server which fails with the given status code
+// for the first number of requests and then succeeds.
+func newFlakyHTTPServer(failures int32, statusCode int) (*httptest.Server, *atomic.Int32) {
+	attempts := new(atomic.Int32)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) <= failures {
+			w.WriteHeader(statusCode)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	return srv, attempts
+}
@@ -0,0 +1,344 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-http-utils/headers"
+)
+
+const (
+	eventStreamMediaType = "text/event-stream"
+	ndjsonMediaType      = "application/x-ndjson"
+	lastEventIDHeader    = "Last-Event-ID"
+
+	// DefaultEventStreamRetry is the delay before reconnecting to an event
+	// stream, unless the server has sent another with the retry field.
+	DefaultEventStreamRetry = 3 * time.Second
+
+	maxEventStreamLineSize = 1 << 20
+)
+
+// StreamJSONArray decodes the elements of a top-level JSON array in the
+// response body one at a time, without reading the whole body into memory.
+// The body is closed when the iteration stops.
+//
+//	for point, err := range client.StreamJSONArray[Point](response) {
+func StreamJSONArray[T any](r *Response) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer closeStream(r)
+
+		var zero T
+
+		decoder := json.NewDecoder(r.Body)
+
+		if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+			if err == nil {
+				err = fmt.Errorf("expected a json array, got %v", token)
+			}
+
+			yield(zero, fmt.Errorf("failed to json decode array: %w", err))
+
+			return
+		}
+
+		for decoder.More() {
+			var v T
+			if err := decoder.Decode(&v); err != nil {
+				yield(zero, fmt.Errorf("failed to json decode array element: %w", err))
+				return
+			}
+
+			if !yield(v, nil) {
+				return
+			}
+		}
+
+		if _, err := decoder.Token(); err != nil {
+			yield(zero, fmt.Errorf("failed to json decode array: %w", err))
+		}
+	}
+}
+
+// StreamNDJSON decodes the newline delimited JSON values of the response body
+// one at a time. The body is closed when the iteration stops.
+func StreamNDJSON[T any](r *Response) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer closeStream(r)
+
+		decoder := json.NewDecoder(r.Body)
+
+		for {
+			var v T
+
+			err := decoder.Decode(&v)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			if err != nil {
+				var zero T
+
+				yield(zero, fmt.Errorf("failed to json decode line: %w", err))
+
+				return
+			}
+
+			if !yield(v, nil) {
+				return
+			}
+		}
+	}
+}
+
+// closeStream closes the body without draining it, such that stopping the
+// iteration early does not wait for the rest of a possibly endless stream.
+func closeStream(r *Response) {
+	r.Body.Close() //nolint: errcheck
+}
+
+// Event is an event of a Server-Sent Events stream.
+type Event struct {
+	// ID is the last event ID of the stream, used to resume it.
+	ID string
+	// Type is the event type, "message" unless specified.
+	Type string
+	Data string
+}
+
+// Events iterates the events of a `text/event-stream` response body. The body
+// is closed when the iteration stops. Use Client.Subscribe to also reconnect
+// when the stream ends.
+func (r *Response) Events() iter.Seq2[Event, error] {
+	return newEventReader(r.Body, "").all(r)
+}
+
+// Subscribe iterates the events of a Server-Sent Events stream, reconnecting
+// with `Last-Event-ID` whenever the stream ends or the connection fails.
+// Errors are yielded and followed by a reconnection, unless the iteration is
+// stopped.
+//
+// Subscribing stops when the context is done, when the server responds with
+// an error or 204 No Content, or when the response is not an event stream.
+// The timeout of the Client, if any, also limits the duration of every
+// connection.
+//
+//	for event, err := range c.Subscribe(ctx, client.Get("feeds/{id}").Assign("id", id)) {
+func (c *Client) Subscribe(ctx context.Context, r *Request) iter.Seq2[Event, error] {
+	return func(yield func(Event, error) bool) {
+		var (
+			lastEventID string
+			retry       = DefaultEventStreamRetry
+		)
+
+		for ctx.Err() == nil {
+			request := r.Clone().SetHeader(headers.Accept, eventStreamMediaType)
+			if lastEventID != "" {
+				request.SetHeader(lastEventIDHeader, lastEventID)
+			}
+
+			response, err := c.Do(ctx, request)
+			if err != nil {
+				// Only failed connections are retried, not failed requests.
+				var urlErr *url.Error
+				if !yield(Event{}, err) || !errors.As(err, &urlErr) {
+					return
+				}
+			} else {
+				events, err := newEventStreamReader(response, lastEventID)
+				if err != nil {
+					yield(Event{}, err)
+					return
+				}
+
+				if events == nil {
+					return
+				}
+
+				for event, err := range events.all(response) {
+					if ctx.Err() != nil || !yield(event, err) {
+						return
+					}
+				}
+
+				lastEventID = events.lastEventID
+				retry = events.retry(retry)
+			}
+
+			if err := sleepContext(ctx, retry); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// newEventStreamReader returns a reader of the events of the response, or nil
+// if the server has responded 204 No Content to end the stream.
+func newEventStreamReader(response *Response, lastEventID string) (*eventReader, error) {
+	if response.StatusCode == http.StatusNoContent {
+		return nil, response.Close()
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(response.Header.Get(headers.ContentType)) //nolint: errcheck
+	if mediaType != eventStreamMediaType {
+		response.Close() //nolint: errcheck
+		return nil, fmt.Errorf("expected an event stream, got content type %q", mediaType)
+	}
+
+	return newEventReader(response.Body, lastEventID), nil
+}
+
+// eventReader parses a Server-Sent Events stream as specified by
+// https://html.spec.whatwg.org/multipage/server-sent-events.html.
+type eventReader struct {
+	scanner     *bufio.Scanner
+	lastEventID string
+	retryDelay  time.Duration
+	started     bool
+}
+
+func newEventReader(r io.Reader, lastEventID string) *eventReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxEventStreamLineSize)
+	scanner.Split(scanEventStreamLines)
+
+	return &eventReader{scanner: scanner, lastEventID: lastEventID}
+}
+
+// all iterates the events until the stream ends, closing the response
+// afterwards.
+func (er *eventReader) all(response *Response) iter.Seq2[Event, error] {
+	return func(yield func(Event, error) bool) {
+		defer closeStream(response)
+
+		for {
+			event, err := er.next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			if !yield(event, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// retry returns the reconnection delay sent by the server, if any.
+func (er *eventReader) retry(fallback time.Duration) time.Duration {
+	if er.retryDelay > 0 {
+		return er.retryDelay
+	}
+
+	return fallback
+}
+
+// next returns the next event, or io.EOF when the stream has ended.
+func (er *eventReader) next() (Event, error) {
+	var (
+		data      strings.Builder
+		eventType string
+		hasData   bool
+	)
+
+	for er.scanner.Scan() {
+		line := er.scanner.Text()
+
+		if !er.started {
+			line = strings.TrimPrefix(line, "\uFEFF")
+			er.started = true
+		}
+
+		if line == "" {
+			if !hasData {
+				eventType = ""
+				continue
+			}
+
+			if eventType == "" {
+				eventType = "message"
+			}
+
+			return Event{ID: er.lastEventID, Type: eventType, Data: data.String()}, nil
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+
+			data.WriteString(value)
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				er.lastEventID = value
+			}
+		case "retry":
+			if milliseconds, err := strconv.ParseUint(value, 10, 63); err == nil {
+				er.retryDelay = time.Duration(milliseconds) * time.Millisecond
+			}
+		}
+	}
+
+	if err := er.scanner.Err(); err != nil {
+		return Event{}, fmt.Errorf("failed to read event stream: %w", err)
+	}
+
+	// An event which is not terminated by an empty line is discarded.
+	return Event{}, io.EOF
+}
+
+// scanEventStreamLines splits lines ended by CRLF, LF or CR.
+func scanEventStreamLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+
+		if i+1 < len(data) {
+			if data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+
+			return i + 1, data[:i], nil
+		}
+
+		if atEOF {
+			return i + 1, data[:i], nil
+		}
+
+		return 0, nil, nil
+	}
+
+	if atEOF {
+		return len(data), data, nil
+	}
+
+	return 0, nil, nil
+}
@@ -0,0 +1,299 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client"
+)
+
+type point struct {
+	X int `json:"x"`
+}
+
+func newStaticHTTPServer(contentType, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set(headers.ContentType, contentType)
+		fmt.Fprint(w, body)
+	}))
+}
+
+func getResponse(t *testing.T, url string) *client.Response {
+	t.Helper()
+
+	response, err := client.NewClient(client.WithBaseURL(url)).Do(context.Background(), client.Get("stream"))
+	require.NoError(t, err)
+
+	return response
+}
+
+func TestStreamJSONArray(t *testing.T) {
+	srv := newStaticHTTPServer("application/json", `[{"x": 1}, {"x": 2},{"x":3}]`)
+	defer srv.Close()
+
+	var points []point
+
+	for p, err := range client.StreamJSONArray[point](getResponse(t, srv.URL)) {
+		require.NoError(t, err)
+
+		points = append(points, p)
+	}
+
+	require.Equal(t, []point{{1}, {2}, {3}}, points)
+
+	for p, err := range client.StreamJSONArray[point](getResponse(t, srv.URL)) {
+		require.NoError(t, err)
+		require.Equal(t, point{1}, p)
+
+		break
+	}
+}
+
+func TestStreamJSONArray_Invalid(t *testing.T) {
+	for _, body := range []string{`{"x": 1}`, `[{"x": 1}, {"x": "two"}]`, `[{"x": 1}`} {
+		srv := newStaticHTTPServer("application/json", body)
+
+		var err error
+
+		for _, err = range client.StreamJSONArray[point](getResponse(t, srv.URL)) {
+			if err != nil {
+				break
+			}
+		}
+
+		require.Error(t, err, body)
+
+		srv.Close()
+	}
+}
+
+func TestStreamNDJSON(t *testing.T) {
+	srv := newStaticHTTPServer("application/x-ndjson", "{\"x\": 1}\n{\"x\": 2}\n\n{\"x\": 3}\n")
+	defer srv.Close()
+
+	var points []point
+
+	for p, err := range client.StreamNDJSON[point](getResponse(t, srv.URL)) {
+		require.NoError(t, err)
+
+		points = append(points, p)
+	}
+
+	require.Equal(t, []point{{1}, {2}, {3}}, points)
+
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "{\"x\": 1}\nnot json\n")
+	})
+
+	var err error
+
+	points = nil
+
+	for p, err2 := range client.StreamNDJSON[point](getResponse(t, srv.URL)) {
+		if err = err2; err != nil {
+			break
+		}
+
+		points = append(points, p)
+	}
+
+	require.Error(t, err)
+	require.Equal(t, []point{{1}}, points)
+}
+
+func TestResponseEvents(t *testing.T) {
+	srv := newStaticHTTPServer("text/event-stream", "\ufeff: comment\r\n"+
+		"data: first\r\n\r\n"+
+		"event: update\n"+
+		"id: 7\n"+
+		"data: second\n"+
+		"data:  line\n\n"+
+		"id\r"+
+		"data\r\r"+
+		"event: ignored\n\n"+
+		"data: unterminated")
+	defer srv.Close()
+
+	var events []client.Event
+
+	for event, err := range getResponse(t, srv.URL).Events() {
+		require.NoError(t, err)
+
+		events = append(events, event)
+	}
+
+	require.Equal(t, []client.Event{
+		{Type: "message", Data: "first"},
+		{ID: "7", Type: "update", Data: "second\n line"},
+		{Type: "message", Data: ""},
+	}, events)
+}
+
+func TestClientSubscribe(t *testing.T) {
+	var (
+		connections  atomic.Int32
+		lastEventIDs = make(chan string, 3)
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastEventIDs <- r.Header.Get("Last-Event-ID")
+
+		switch connections.Add(1) {
+		case 1:
+			w.Header().Set(headers.ContentType, "text/event-stream")
+			fmt.Fprint(w, "retry: 10\n\nid: 1\ndata: one\n\nid: 2\ndata: two\n\n")
+		case 2:
+			w.Header().Set(headers.ContentType, "text/event-stream; charset=utf-8")
+			fmt.Fprint(w, "id: 3\ndata: three\n\n")
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	var data []string
+
+	for event, err := range c.Subscribe(context.Background(), client.Get("events")) {
+		require.NoError(t, err)
+
+		data = append(data, event.Data)
+	}
+
+	require.Equal(t, []string{"one", "two", "three"}, data)
+	require.Equal(t, "", <-lastEventIDs)
+	require.Equal(t, "2", <-lastEventIDs)
+	require.Equal(t, "3", <-lastEventIDs)
+}
+
+func TestClientSubscribe_Error(t *testing.T) {
+	srv := newStaticHTTPServer("application/json", `{}`)
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	var errs []error
+
+	for _, err := range c.Subscribe(context.Background(), client.Get("events")) {
+		errs = append(errs, err)
+	}
+
+	require.Len(t, errs, 1)
+	require.ErrorContains(t, errs[0], "expected an event stream")
+
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	errs = nil
+
+	for _, err := range c.Subscribe(context.Background(), client.Get("events")) {
+		errs = append(errs, err)
+	}
+
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], client.ErrNotFound)
+}
+
+// newEndlessHTTPServer writes the first chunk and then the next chunk every
+// millisecond until the client disconnects or the test has ended. The stream
+// has a validator, such that a cache would try to store it.
+func newEndlessHTTPServer(t *testing.T, contentType, first, next string) *httptest.Server {
+	t.Helper()
+
+	stop := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headers.ContentType, contentType)
+		w.Header().Set(headers.ETag, `"endless"`)
+		fmt.Fprint(w, first)
+
+		for {
+			w.(http.Flusher).Flush()
+
+			select {
+			case <-r.Context().Done():
+				return
+			case <-stop:
+				return
+			case <-time.After(time.Millisecond):
+				fmt.Fprint(w, next)
+			}
+		}
+	}))
+
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(stop) })
+
+	return srv
+}
+
+func requireReturns(t *testing.T, fn func()) {
+	t.Helper()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		fn()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("iteration did not stop")
+	}
+}
+
+func TestStream_BreakEndlessStream(t *testing.T) {
+	sse := newEndlessHTTPServer(t, "text/event-stream", "data: first\n\n", ": keep-alive\n\n")
+	array := newEndlessHTTPServer(t, "application/json", `[{"x": 1}`, `, {"x": 2}`)
+	ndjson := newEndlessHTTPServer(t, "application/x-ndjson", "{\"x\": 1}\n", "{\"x\": 2}\n")
+
+	requireReturns(t, func() {
+		for event, err := range getResponse(t, sse.URL).Events() {
+			require.NoError(t, err)
+			require.Equal(t, "first", event.Data)
+
+			break
+		}
+	})
+
+	requireReturns(t, func() {
+		c := client.NewClient(client.WithBaseURL(sse.URL))
+
+		for event, err := range c.Subscribe(context.Background(), client.Get("events")) {
+			require.NoError(t, err)
+			require.Equal(t, "first", event.Data)
+
+			break
+		}
+	})
+
+	requireReturns(t, func() {
+		for p, err := range client.StreamJSONArray[point](getResponse(t, array.URL)) {
+			require.NoError(t, err)
+			require.Equal(t, point{1}, p)
+
+			break
+		}
+	})
+
+	requireReturns(t, func() {
+		for p, err := range client.StreamNDJSON[point](getResponse(t, ndjson.URL)) {
+			require.NoError(t, err)
+			require.Equal(t, point{1}, p)
+
+			break
+		}
+	})
+}
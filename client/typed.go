@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-http-utils/headers"
+)
+
+// ResponseMeta describes the response of a request whose body has already
+// been consumed.
+type ResponseMeta struct {
+	StatusCode int
+	Header     http.Header
+
+	// ETag is the entity tag of the returned representation, if any.
+	ETag string
+
+	// Location is the URI the server referred to, such as a created resource,
+	// if any.
+	Location string
+}
+
+func newResponseMeta(response *Response) *ResponseMeta {
+	return &ResponseMeta{
+		StatusCode: response.StatusCode,
+		Header:     response.Header,
+		ETag:       response.ETag(),
+		Location:   response.Header.Get(headers.Location),
+	}
+}
+
+// DoJSON executes the request and decodes the response body into a value of
+// type T. Responses without a body result in the zero value of T.
+//
+//	node, meta, err := client.DoJSON[Node](ctx, c, client.Get("nodes/{id}").Assign("id", id))
+func DoJSON[T any](ctx context.Context, c *Client, r *Request) (T, *ResponseMeta, error) {
+	response, err := c.Do(ctx, r)
+	if err != nil {
+		var v T
+		return v, nil, err
+	}
+
+	defer response.Close()
+
+	return decodeResponse[T](response)
+}
+
+// decodeResponse decodes the body of the response into a value of type T,
+// responses without a body result in the zero value of T.
+func decodeResponse[T any](response *Response) (T, *ResponseMeta, error) {
+	var v T
+
+	meta := newResponseMeta(response)
+
+	if response.StatusCode == http.StatusNoContent || response.ContentLength == 0 {
+		return v, meta, nil
+	}
+
+	if err := response.Unmarshal(&v); err != nil {
+		return v, meta, err
+	}
+
+	return v, meta, nil
+}
+
+// Send encodes the payload as JSON into the body of the request, executes it
+// and decodes the response body into a value of type Resp.
+//
+//	created, meta, err := client.Send[CreateNode, Node](ctx, c, client.Post("nodes"), node)
+func Send[Req, Resp any](ctx context.Context, c *Client, r *Request, payload Req) (Resp, *ResponseMeta, error) {
+	return DoJSON[Resp](ctx, c, r.Clone().WithJSONPayload(payload))
+}
@@ -0,0 +1,90 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client"
+)
+
+type typedNode struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+func TestDoJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headers.ETag, `"v1"`)
+		json.NewEncoder(w).Encode(typedNode{ID: r.URL.Path[len("/nodes/"):], Label: "Pump"}) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	node, meta, err := client.DoJSON[typedNode](context.Background(), c, client.Get("nodes/{id}").Assign("id", "42"))
+	require.NoError(t, err)
+
+	require.Equal(t, typedNode{ID: "42", Label: "Pump"}, node)
+	require.Equal(t, http.StatusOK, meta.StatusCode)
+	require.Equal(t, `"v1"`, meta.ETag)
+}
+
+func TestDoJSON_NoContent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	node, meta, err := client.DoJSON[*typedNode](context.Background(), c, client.Delete("nodes/42"))
+	require.NoError(t, err)
+
+	require.Nil(t, node)
+	require.Equal(t, http.StatusNoContent, meta.StatusCode)
+}
+
+func TestDoJSON_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	_, meta, err := client.DoJSON[typedNode](context.Background(), c, client.Get("nodes/42"))
+	require.ErrorIs(t, err, client.ErrNotFound)
+	require.Nil(t, meta)
+}
+
+func TestSend(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var node typedNode
+		if err := json.NewDecoder(r.Body).Decode(&node); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		node.ID = "43"
+
+		w.Header().Set(headers.Location, "/nodes/43")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(node) //nolint: errcheck
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	created, meta, err := client.Send[typedNode, typedNode](context.Background(), c, client.Post("nodes"), typedNode{Label: "Motor"})
+	require.NoError(t, err)
+
+	require.Equal(t, typedNode{ID: "43", Label: "Motor"}, created)
+	require.Equal(t, http.StatusCreated, meta.StatusCode)
+	require.Equal(t, "/nodes/43", meta.Location)
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-http-utils/headers"
+)
+
+const DefaultUpdateAttempts = 5
+
+var ErrMissingETag = errors.New("response is missing an etag")
+
+// UpdateWithRetry updates the JSON resource of the GET request with
+// optimistic concurrency. The resource is fetched, changed by mutate and then
+// PUT back with `If-Match` set to its entity tag.
+//
+// If the resource was changed by someone else in between, the server
+// responds with ErrPreconditionFailed and the update is started over with a
+// fresh copy of the resource, at most attempts times in total, or
+// DefaultUpdateAttempts if not positive.
+//
+// The returned value is the response of the PUT request, or the value sent
+// if the response has no body.
+//
+//	node, meta, err := client.UpdateWithRetry(ctx, c, client.Get("nodes/{id}").Assign("id", id), 0,
+//		func(node *Node) error {
+//			node.Label = label
+//			return nil
+//		})
+func UpdateWithRetry[T any](ctx context.Context, c *Client, r *Request, attempts int, mutate func(*T) error) (T, *ResponseMeta, error) {
+	if attempts <= 0 {
+		attempts = DefaultUpdateAttempts
+	}
+
+	var (
+		updated T
+		meta    *ResponseMeta
+		err     error
+	)
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		updated, meta, err = update(ctx, c, r, mutate)
+		if !errors.Is(err, ErrPreconditionFailed) {
+			break
+		}
+	}
+
+	return updated, meta, err
+}
+
+func update[T any](ctx context.Context, c *Client, r *Request, mutate func(*T) error) (T, *ResponseMeta, error) {
+	// The resource is revalidated, as a cached copy may be the very copy the
+	// precondition of the previous attempt failed with.
+	get := r.Clone().SetHeader(headers.CacheControl, "no-cache")
+
+	value, meta, err := DoJSON[T](ctx, c, get)
+	if err != nil {
+		return value, meta, err
+	}
+
+	if meta.ETag == "" {
+		return value, meta, ErrMissingETag
+	}
+
+	if err = mutate(&value); err != nil {
+		return value, meta, fmt.Errorf("unable to mutate resource: %w", err)
+	}
+
+	put := r.Clone()
+	put.method = http.MethodPut
+
+	updated, meta, err := DoJSON[T](ctx, c, put.IfMatch(meta.ETag).WithJSONPayload(value))
+	if err != nil {
+		return updated, meta, err
+	}
+
+	if meta.StatusCode == http.StatusNoContent || meta.Header.Get(headers.ContentLength) == "0" {
+		return value, meta, nil
+	}
+
+	return updated, meta, nil
+}
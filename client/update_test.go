@@ -0,0 +1,147 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SKF/go-rest-utility/client"
+	"github.com/SKF/go-rest-utility/client/cache"
+)
+
+type versionedNode struct {
+	Label   string `json:"label"`
+	Counter int    `json:"counter"`
+}
+
+// newVersionedHTTPServer serves a single cacheable node whose first
+// concurrentWrites PUT requests are preceded by a write of someone else.
+func newVersionedHTTPServer(concurrentWrites int) (*httptest.Server, *int) {
+	var (
+		mu      sync.Mutex
+		node    versionedNode
+		version int
+		puts    int
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		etag := func() string { return `"` + strconv.Itoa(version) + `"` }
+
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set(headers.CacheControl, "max-age=60")
+			w.Header().Set(headers.ETag, etag())
+
+			if r.Header.Get(headers.IfNoneMatch) == etag() {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			json.NewEncoder(w).Encode(node) //nolint: errcheck
+		case http.MethodPut:
+			if puts++; puts <= concurrentWrites {
+				node.Counter++
+				version++
+			}
+
+			if r.Header.Get(headers.IfMatch) != etag() {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+
+			json.NewDecoder(r.Body).Decode(&node) //nolint: errcheck
+			version++
+
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+
+	return srv, &puts
+}
+
+func TestUpdateWithRetry(t *testing.T) {
+	srv, puts := newVersionedHTTPServer(2)
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	node, meta, err := client.UpdateWithRetry(context.Background(), c, client.Get("node"), 0,
+		func(node *versionedNode) error {
+			node.Counter++
+			node.Label = "updated"
+
+			return nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, meta.StatusCode)
+	require.Equal(t, versionedNode{Label: "updated", Counter: 3}, node)
+	require.Equal(t, 3, *puts)
+}
+
+func TestUpdateWithRetry_WithCache(t *testing.T) {
+	srv, puts := newVersionedHTTPServer(2)
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL), client.WithCache(cache.NewMemoryStore(10)))
+
+	_, _, err := client.DoJSON[versionedNode](context.Background(), c, client.Get("node"))
+	require.NoError(t, err)
+
+	node, _, err := client.UpdateWithRetry(context.Background(), c, client.Get("node"), 0,
+		func(node *versionedNode) error {
+			node.Label = "updated"
+			return nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, versionedNode{Label: "updated", Counter: 2}, node)
+	require.Equal(t, 3, *puts)
+}
+
+func TestUpdateWithRetry_AttemptsExhausted(t *testing.T) {
+	srv, puts := newVersionedHTTPServer(10)
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	_, _, err := client.UpdateWithRetry(context.Background(), c, client.Get("node"), 2,
+		func(*versionedNode) error { return nil })
+	require.ErrorIs(t, err, client.ErrPreconditionFailed)
+	require.Equal(t, 2, *puts)
+}
+
+func TestUpdateWithRetry_MissingETag(t *testing.T) {
+	srv := newEchoHTTPServer()
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	_, _, err := client.UpdateWithRetry(context.Background(), c, client.Get("node"), 0,
+		func(*RequestEcho) error { return nil })
+	require.ErrorIs(t, err, client.ErrMissingETag)
+}
+
+func TestRequestConditionalHeaders(t *testing.T) {
+	srv := newEchoHTTPServer()
+	defer srv.Close()
+
+	c := client.NewClient(client.WithBaseURL(srv.URL))
+
+	var echo RequestEcho
+
+	response, err := c.Do(context.Background(), client.Put("node").IfMatch(`"1"`).IfNoneMatch("*"))
+	require.NoError(t, err)
+	require.NoError(t, response.Unmarshal(&echo))
+
+	require.Equal(t, `"1"`, echo.Header.Get(headers.IfMatch))
+	require.Equal(t, "*", echo.Header.Get(headers.IfNoneMatch))
+}
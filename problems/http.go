@@ -9,7 +9,13 @@ import (
 	"github.com/SKF/go-utility/v2/log"
 )
 
-const ContentType = "application/problem+json"
+const (
+	ContentType = "application/problem+json"
+
+	// XMLContentType, the media type of problems in the XML format described
+	// in RFC 9457 Appendix B.
+	XMLContentType = "application/problem+xml"
+)
 
 // Generic, returns a generic HTTP-based Problem from a HTTP status code.
 func Generic(status int) Problem {